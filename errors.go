@@ -129,7 +129,16 @@ var (
 	ErrPivot               = Error{"matrix: malformed pivot list"}
 	ErrTriangle            = Error{"matrix: triangular storage mismatch"}
 	ErrTriangleSet         = Error{"matrix: triangular set out of bounds"}
+	ErrBandSet             = Error{"matrix: band set out of bounds"}
 	ErrSliceLengthMismatch = Error{"matrix: input slice length mismatch"}
+	ErrToeplitzCorner      = Error{"matrix: toeplitz column and row differ at corner"}
+	ErrHankelCorner        = Error{"matrix: hankel column and row differ at corner"}
+	ErrZeroMatrix          = Error{"matrix: zero matrix"}
+	ErrNoConvergence       = Error{"matrix: factorization did not converge"}
+	ErrNotSymmetric        = Error{"matrix: matrix not symmetric"}
+	ErrNotPositiveDefinite = Error{"matrix: matrix not positive definite"}
+	ErrZeroLeadingCoeff    = Error{"matrix: zero leading polynomial coefficient"}
+	ErrRegularization      = Error{"matrix: negative or NaN regularization parameter"}
 )
 
 // ErrorStack represents matrix handling errors that have been recovered by Maybe wrappers.
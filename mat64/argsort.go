@@ -0,0 +1,198 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"sort"
+
+	"github.com/gonum/matrix"
+)
+
+// ArgsortRows returns, for each row of the receiver, the column indices that
+// would sort that row in ascending order. Equal elements retain the order of
+// their indices. If dst is nil, a new slice of index slices is allocated and
+// returned. Otherwise dst must have one element per row, each with one index
+// per column, and ArgsortRows will panic with matrix.ErrSliceLengthMismatch
+// if it does not.
+func (m *Dense) ArgsortRows(dst [][]int) [][]int {
+	return m.argsort(dst, false, false)
+}
+
+// ArgsortRowsDesc is as ArgsortRows, but the indices sort each row in
+// descending order.
+func (m *Dense) ArgsortRowsDesc(dst [][]int) [][]int {
+	return m.argsort(dst, false, true)
+}
+
+// ArgsortCols returns, for each column of the receiver, the row indices that
+// would sort that column in ascending order. Equal elements retain the order of
+// their indices. If dst is nil, a new slice of index slices is allocated and
+// returned. Otherwise dst must have one element per column, each with one index
+// per row, and ArgsortCols will panic with matrix.ErrSliceLengthMismatch
+// if it does not.
+func (m *Dense) ArgsortCols(dst [][]int) [][]int {
+	return m.argsort(dst, true, false)
+}
+
+// ArgsortColsDesc is as ArgsortCols, but the indices sort each column in
+// descending order.
+func (m *Dense) ArgsortColsDesc(dst [][]int) [][]int {
+	return m.argsort(dst, true, true)
+}
+
+func (m *Dense) argsort(dst [][]int, cols, desc bool) [][]int {
+	r, c := m.Dims()
+	n, l, inc := r, c, 1
+	if cols {
+		n, l, inc = c, r, m.mat.Stride
+	}
+	if dst == nil {
+		dst = make([][]int, n)
+		for i := range dst {
+			dst[i] = make([]int, l)
+		}
+	}
+	if len(dst) != n {
+		panic(matrix.ErrSliceLengthMismatch)
+	}
+	for _, inds := range dst {
+		if len(inds) != l {
+			panic(matrix.ErrSliceLengthMismatch)
+		}
+	}
+
+	vals := make([]float64, l)
+	for i, inds := range dst {
+		off := i * m.mat.Stride
+		if cols {
+			off = i
+		}
+		for j := range vals {
+			vals[j] = m.mat.Data[off+j*inc]
+			inds[j] = j
+		}
+		sort.Stable(argsorter{vals: vals, inds: inds, desc: desc})
+	}
+	return dst
+}
+
+// argsorter sorts inds by the values in vals they index.
+type argsorter struct {
+	vals []float64
+	inds []int
+	desc bool
+}
+
+func (a argsorter) Len() int { return len(a.inds) }
+func (a argsorter) Less(i, j int) bool {
+	if a.desc {
+		return a.vals[a.inds[i]] > a.vals[a.inds[j]]
+	}
+	return a.vals[a.inds[i]] < a.vals[a.inds[j]]
+}
+func (a argsorter) Swap(i, j int) { a.inds[i], a.inds[j] = a.inds[j], a.inds[i] }
+
+// TopKRows returns the column indices and values of the k largest elements in
+// each row of the receiver, ordered by decreasing value. Row i of indices holds
+// the column indices of the elements in row i of values. Equal elements are
+// ordered by increasing column index. If k is greater than the number of
+// columns, it is clamped to the number of columns. TopKRows panics if k is
+// not positive.
+//
+// The selection uses a bounded heap for each row, taking O(c log k) time per
+// row for a receiver with c columns rather than the O(c log c) of a full sort.
+func (m *Dense) TopKRows(k int) (indices, values *Dense) {
+	if k < 1 {
+		panic("mat64: non-positive k")
+	}
+	r, c := m.Dims()
+	k = min(k, c)
+	indices = NewDense(r, k, nil)
+	values = NewDense(r, k, nil)
+	heap := make([]int, k)
+	for i := 0; i < r; i++ {
+		row := m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+c]
+		h := topKHeap{vals: row, inds: heap[:0]}
+		for j := range row {
+			h.offer(j, k)
+		}
+		// Popping the heap yields the selected elements in
+		// increasing order, so fill the result from the end.
+		irow := indices.mat.Data[i*indices.mat.Stride : i*indices.mat.Stride+k]
+		vrow := values.mat.Data[i*values.mat.Stride : i*values.mat.Stride+k]
+		for l := k - 1; l >= 0; l-- {
+			j := h.pop()
+			irow[l] = float64(j)
+			vrow[l] = row[j]
+		}
+	}
+	return indices, values
+}
+
+// topKHeap is a binary min-heap of indices into vals, ordered so that the root
+// is the least preferred element held. An element is preferred to another if
+// its value is larger, or if the values are equal and its index is lower.
+type topKHeap struct {
+	vals []float64
+	inds []int
+}
+
+// worse returns whether the element at a is less preferred than that at b.
+func (h *topKHeap) worse(a, b int) bool {
+	va, vb := h.vals[a], h.vals[b]
+	return va < vb || (va == vb && a > b)
+}
+
+// offer adds the index j to a heap holding at most k elements, replacing
+// the root if the heap is full and j is preferred to it.
+func (h *topKHeap) offer(j, k int) {
+	if len(h.inds) < k {
+		h.inds = append(h.inds, j)
+		// Sift up.
+		for c := len(h.inds) - 1; c > 0; {
+			p := (c - 1) / 2
+			if !h.worse(h.inds[c], h.inds[p]) {
+				break
+			}
+			h.inds[c], h.inds[p] = h.inds[p], h.inds[c]
+			c = p
+		}
+		return
+	}
+	if h.worse(j, h.inds[0]) {
+		return
+	}
+	h.inds[0] = j
+	h.down()
+}
+
+// pop removes and returns the least preferred element of the heap.
+func (h *topKHeap) pop() int {
+	j := h.inds[0]
+	last := len(h.inds) - 1
+	h.inds[0] = h.inds[last]
+	h.inds = h.inds[:last]
+	h.down()
+	return j
+}
+
+// down restores the heap order from the root.
+func (h *topKHeap) down() {
+	n := len(h.inds)
+	for p := 0; ; {
+		c := 2*p + 1
+		if c >= n {
+			return
+		}
+		if c+1 < n && h.worse(h.inds[c+1], h.inds[c]) {
+			c++
+		}
+		if !h.worse(h.inds[c], h.inds[p]) {
+			return
+		}
+		h.inds[c], h.inds[p] = h.inds[p], h.inds[c]
+		p = c
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gonum/matrix"
+)
+
+func TestArgsort(t *testing.T) {
+	for _, test := range []struct {
+		m    *Dense
+		rows [][]int
+	}{
+		{
+			m: NewDense(3, 4, []float64{
+				3, 1, 2, 0,
+				-1, 5, 5, 2,
+				7, 7, 7, 7,
+			}),
+			// Equal elements retain the order of their indices.
+			rows: [][]int{
+				{3, 1, 2, 0},
+				{0, 3, 1, 2},
+				{0, 1, 2, 3},
+			},
+		},
+	} {
+		if got := test.m.ArgsortRows(nil); !reflect.DeepEqual(got, test.rows) {
+			t.Errorf("unexpected row argsort: got: %v want: %v", got, test.rows)
+		}
+	}
+
+	for _, test := range []struct {
+		r, c int
+	}{
+		{1, 1},
+		{1, 7},
+		{7, 1},
+		{5, 8},
+		{9, 3},
+	} {
+		m := NewDense(test.r, test.c, nil)
+		for i := 0; i < test.r; i++ {
+			for j := 0; j < test.c; j++ {
+				// Use a small range of integers to create ties.
+				m.Set(i, j, float64(rand.Intn(5)))
+			}
+		}
+		for _, desc := range []bool{false, true} {
+			var rows, cols [][]int
+			if desc {
+				rows = m.ArgsortRowsDesc(nil)
+				cols = m.ArgsortColsDesc(nil)
+			} else {
+				rows = m.ArgsortRows(nil)
+				cols = m.ArgsortCols(nil)
+			}
+			if len(rows) != test.r {
+				t.Fatalf("unexpected number of rows: got: %d want: %d", len(rows), test.r)
+			}
+			for i, inds := range rows {
+				checkArgsorted(t, Row(nil, i, m), inds, desc)
+			}
+			if len(cols) != test.c {
+				t.Fatalf("unexpected number of columns: got: %d want: %d", len(cols), test.c)
+			}
+			for j, inds := range cols {
+				checkArgsorted(t, Col(nil, j, m), inds, desc)
+			}
+		}
+
+		// Check that a provided destination is used.
+		dst := make([][]int, test.r)
+		for i := range dst {
+			dst[i] = make([]int, test.c)
+		}
+		got := m.ArgsortRows(dst)
+		if &got[0][0] != &dst[0][0] {
+			t.Error("destination not used")
+		}
+		if !reflect.DeepEqual(got, m.ArgsortRows(nil)) {
+			t.Error("unexpected argsort into destination")
+		}
+	}
+
+	m := NewDense(2, 3, nil)
+	for _, dst := range [][][]int{
+		make([][]int, 3),
+		{make([]int, 3), make([]int, 2)},
+	} {
+		p, msg := panics(func() { m.ArgsortRows(dst) })
+		if !p || msg != matrix.ErrSliceLengthMismatch.Error() {
+			t.Errorf("unexpected panic for bad destination: got: %q want: %q", msg, matrix.ErrSliceLengthMismatch)
+		}
+	}
+}
+
+// checkArgsorted checks that inds is a permutation of the indices of vals
+// that orders vals, with ties in index order.
+func checkArgsorted(t *testing.T, vals []float64, inds []int, desc bool) {
+	if len(inds) != len(vals) {
+		t.Errorf("unexpected index length: got: %d want: %d", len(inds), len(vals))
+		return
+	}
+	seen := make([]bool, len(vals))
+	for _, i := range inds {
+		if seen[i] {
+			t.Errorf("indices not a permutation: %v", inds)
+			return
+		}
+		seen[i] = true
+	}
+	sorted := make([]float64, len(vals))
+	for k, i := range inds {
+		sorted[k] = vals[i]
+		if desc {
+			sorted[k] = -sorted[k]
+		}
+	}
+	if !sort.Float64sAreSorted(sorted) {
+		t.Errorf("indices do not sort values: vals: %v inds: %v", vals, inds)
+	}
+	for k := 1; k < len(inds); k++ {
+		if vals[inds[k]] == vals[inds[k-1]] && inds[k] < inds[k-1] {
+			t.Errorf("tied indices out of order: vals: %v inds: %v", vals, inds)
+		}
+	}
+}
+
+func TestTopKRows(t *testing.T) {
+	for _, test := range []struct {
+		r, c, k int
+	}{
+		{1, 1, 1},
+		{3, 5, 1},
+		{3, 5, 3},
+		{4, 6, 6},
+		{4, 6, 10},
+		{10, 20, 7},
+	} {
+		m := NewDense(test.r, test.c, nil)
+		for i := 0; i < test.r; i++ {
+			for j := 0; j < test.c; j++ {
+				// Use a small range of integers to create ties.
+				m.Set(i, j, float64(rand.Intn(6)-3))
+			}
+		}
+		k := min(test.k, test.c)
+
+		indices, values := m.TopKRows(test.k)
+		if r, c := indices.Dims(); r != test.r || c != k {
+			t.Errorf("unexpected indices shape: got: %d×%d want: %d×%d", r, c, test.r, k)
+			continue
+		}
+		if r, c := values.Dims(); r != test.r || c != k {
+			t.Errorf("unexpected values shape: got: %d×%d want: %d×%d", r, c, test.r, k)
+			continue
+		}
+
+		// The brute force answer is a full descending stable sort.
+		want := m.ArgsortRowsDesc(nil)
+		for i := 0; i < test.r; i++ {
+			for l := 0; l < k; l++ {
+				j := want[i][l]
+				if got := int(indices.At(i, l)); got != j {
+					t.Errorf("unexpected index at (%d,%d) for k=%d: got: %d want: %d", i, l, test.k, got, j)
+				}
+				if got := values.At(i, l); got != m.At(i, j) {
+					t.Errorf("unexpected value at (%d,%d) for k=%d: got: %v want: %v", i, l, test.k, got, m.At(i, j))
+				}
+			}
+		}
+	}
+
+	for _, k := range []int{0, -1} {
+		if p, _ := panics(func() { NewDense(2, 2, nil).TopKRows(k) }); !p {
+			t.Errorf("expected panic for k=%d", k)
+		}
+	}
+}
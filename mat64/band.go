@@ -0,0 +1,189 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"github.com/gonum/blas/blas64"
+	"github.com/gonum/matrix"
+)
+
+var (
+	bandDense *BandDense
+	_         Matrix    = bandDense
+	_         Banded    = bandDense
+	_         RawBander = bandDense
+)
+
+// BandDense represents a band matrix in dense storage format.
+type BandDense struct {
+	mat blas64.Band
+}
+
+// Banded is a band matrix representation.
+type Banded interface {
+	Matrix
+	// Bandwidth returns the lower and upper bandwidth values for
+	// the matrix. The total bandwidth of the matrix is kl+ku+1.
+	Bandwidth() (kl, ku int)
+
+	// TBand is the equivalent of the T() method in the Matrix
+	// interface but guarantees the transpose is of banded type.
+	TBand() Banded
+}
+
+// A RawBander can return a view of itself as a BLAS Band matrix.
+type RawBander interface {
+	RawBand() blas64.Band
+}
+
+var (
+	_ Matrix = TransposeBand{}
+	_ Banded = TransposeBand{}
+)
+
+// TransposeBand is a type for performing an implicit transpose of a band
+// matrix. It implements the Banded interface, returning values from the
+// transpose of the matrix within.
+type TransposeBand struct {
+	Banded Banded
+}
+
+// At returns the value of the element at row i and column j of the transposed
+// matrix, that is, row j and column i of the Banded field.
+func (t TransposeBand) At(i, j int) float64 {
+	return t.Banded.At(j, i)
+}
+
+// Dims returns the dimensions of the transposed matrix.
+func (t TransposeBand) Dims() (r, c int) {
+	c, r = t.Banded.Dims()
+	return r, c
+}
+
+// T performs an implicit transpose by returning the Banded field.
+func (t TransposeBand) T() Matrix {
+	return t.Banded
+}
+
+// Bandwidth returns the lower and upper bandwidth values for
+// the transposed matrix.
+func (t TransposeBand) Bandwidth() (kl, ku int) {
+	kl, ku = t.Banded.Bandwidth()
+	return ku, kl
+}
+
+// TBand performs an implicit transpose by returning the Banded field.
+func (t TransposeBand) TBand() Banded {
+	return t.Banded
+}
+
+// Untranspose returns the Banded field.
+func (t TransposeBand) Untranspose() Matrix {
+	return t.Banded
+}
+
+// NewBandDense creates a new r×c band matrix with kl sub-diagonals and ku
+// super-diagonals. If len(data) == r*(kl+ku+1), data will be used
+// to hold the underlying data, if data == nil, new data will be allocated,
+// and NewBandDense will panic with matrix.ErrShape if neither of these cases
+// is true. NewBandDense will also panic if kl is not less than r or ku is not
+// less than c.
+//
+// The data must be arranged in row-major order with the diagonals aligned in
+// columns, as in the BLAS band storage scheme. Row i of data holds the
+// elements of row i of the matrix from column i-kl to column i+ku, so that
+// element (i, j) is stored at data[i*(kl+ku+1)+j-i+kl]. For example, the
+// 6×6 tridiagonal matrix
+//  1 2 0 0 0 0
+//  3 4 5 0 0 0
+//  0 6 7 8 0 0
+//  0 0 9 10 11 0
+//  0 0 0 12 13 14
+//  0 0 0 0 15 16
+// with kl == ku == 1 is stored as
+//  data := []float64{
+//  	*, 1, 2,
+//  	3, 4, 5,
+//  	6, 7, 8,
+//  	9, 10, 11,
+//  	12, 13, 14,
+//  	15, 16, *,
+//  }
+// where entries marked * are not referenced.
+func NewBandDense(r, c, kl, ku int, data []float64) *BandDense {
+	if r < 0 || c < 0 || kl < 0 || ku < 0 {
+		panic("mat64: negative dimension")
+	}
+	if kl+1 > r || ku+1 > c {
+		panic("mat64: band out of range")
+	}
+	bc := kl + ku + 1
+	if data != nil && len(data) != r*bc {
+		panic(matrix.ErrShape)
+	}
+	if data == nil {
+		data = make([]float64, r*bc)
+	}
+	return &BandDense{
+		mat: blas64.Band{
+			Rows:   r,
+			Cols:   c,
+			KL:     kl,
+			KU:     ku,
+			Stride: bc,
+			Data:   data,
+		},
+	}
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (b *BandDense) Dims() (r, c int) {
+	return b.mat.Rows, b.mat.Cols
+}
+
+// Bandwidth returns the upper and lower bandwidths of the matrix.
+func (b *BandDense) Bandwidth() (kl, ku int) {
+	return b.mat.KL, b.mat.KU
+}
+
+// T performs an implicit transpose by returning the receiver inside a Transpose.
+func (b *BandDense) T() Matrix {
+	return Transpose{b}
+}
+
+// TBand performs an implicit transpose by returning the receiver inside a TransposeBand.
+func (b *BandDense) TBand() Banded {
+	return TransposeBand{b}
+}
+
+// RawBand returns the underlying blas64.Band used by the receiver.
+// Changes to elements in the receiver following the call will be
+// reflected in returned blas64.Band.
+func (b *BandDense) RawBand() blas64.Band {
+	return b.mat
+}
+
+// bandMulVec computes y = A*x, or y = A^T*x if trans is true, for the band
+// matrix A.
+func bandMulVec(y blas64.Vector, trans bool, a blas64.Band, x blas64.Vector) {
+	n := a.Rows
+	if trans {
+		n = a.Cols
+	}
+	for i := 0; i < n; i++ {
+		y.Data[i*y.Inc] = 0
+	}
+	for i := 0; i < a.Rows; i++ {
+		row := a.Data[i*a.Stride : i*a.Stride+a.KL+a.KU+1]
+		for j := max(0, i-a.KL); j < min(a.Cols, i+a.KU+1); j++ {
+			v := row[j+a.KL-i]
+			if trans {
+				y.Data[j*y.Inc] += v * x.Data[i*x.Inc]
+			} else {
+				y.Data[i*y.Inc] += v * x.Data[j*x.Inc]
+			}
+		}
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/matrix"
+)
+
+func TestNewBand(t *testing.T) {
+	// Entries of the band storage outside the matrix are never referenced.
+	nan := math.NaN()
+	for i, test := range []struct {
+		data   []float64
+		r, c   int
+		kl, ku int
+		dense  *Dense
+	}{
+		{
+			data: []float64{
+				nan, 1, 2,
+				3, 4, 5,
+				6, 7, 8,
+				9, 10, 11,
+				12, 13, 14,
+				15, 16, nan,
+			},
+			r: 6, c: 6,
+			kl: 1, ku: 1,
+			dense: NewDense(6, 6, []float64{
+				1, 2, 0, 0, 0, 0,
+				3, 4, 5, 0, 0, 0,
+				0, 6, 7, 8, 0, 0,
+				0, 0, 9, 10, 11, 0,
+				0, 0, 0, 12, 13, 14,
+				0, 0, 0, 0, 15, 16,
+			}),
+		},
+		{
+			data: []float64{
+				nan, 1, 2, 3,
+				4, 5, 6, 7,
+				8, 9, 10, nan,
+			},
+			r: 3, c: 4,
+			kl: 1, ku: 2,
+			dense: NewDense(3, 4, []float64{
+				1, 2, 3, 0,
+				4, 5, 6, 7,
+				0, 8, 9, 10,
+			}),
+		},
+		{
+			data: []float64{
+				nan, nan, 1,
+				nan, 2, 3,
+				4, 5, 6,
+				7, 8, nan,
+				9, nan, nan,
+			},
+			r: 5, c: 3,
+			kl: 2, ku: 0,
+			dense: NewDense(5, 3, []float64{
+				1, 0, 0,
+				2, 3, 0,
+				4, 5, 6,
+				0, 7, 8,
+				0, 0, 9,
+			}),
+		},
+		{
+			data: []float64{
+				1, 2,
+				3, nan,
+				nan, nan,
+				nan, nan,
+			},
+			r: 4, c: 2,
+			kl: 0, ku: 1,
+			dense: NewDense(4, 2, []float64{
+				1, 2,
+				0, 3,
+				0, 0,
+				0, 0,
+			}),
+		},
+	} {
+		band := NewBandDense(test.r, test.c, test.kl, test.ku, test.data)
+		r, c := band.Dims()
+		if r != test.r || c != test.c {
+			t.Errorf("unexpected dimensions for test %d: got: %d×%d want: %d×%d", i, r, c, test.r, test.c)
+		}
+		kl, ku := band.Bandwidth()
+		if kl != test.kl || ku != test.ku {
+			t.Errorf("unexpected bandwidth for test %d: got: %d,%d want: %d,%d", i, kl, ku, test.kl, test.ku)
+		}
+		if !Equal(band, test.dense) {
+			t.Errorf("unexpected band matrix for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(band), Formatted(test.dense))
+		}
+		if !Equal(band.T(), test.dense.T()) {
+			t.Errorf("unexpected transpose for test %d", i)
+		}
+		tBand := band.TBand()
+		if kl, ku := tBand.Bandwidth(); kl != test.ku || ku != test.kl {
+			t.Errorf("unexpected transpose bandwidth for test %d: got: %d,%d want: %d,%d", i, kl, ku, test.ku, test.kl)
+		}
+		if !Equal(tBand, test.dense.T()) {
+			t.Errorf("unexpected band transpose for test %d", i)
+		}
+		if tBand.TBand() != Banded(band) {
+			t.Errorf("double transpose does not return the original for test %d", i)
+		}
+	}
+
+	for _, test := range []struct {
+		r, c, kl, ku int
+		data         []float64
+		want         string
+	}{
+		{r: 3, c: 3, kl: 1, ku: 1, data: make([]float64, 8), want: matrix.ErrShape.Error()},
+		{r: 3, c: 3, kl: 3, ku: 0, want: "mat64: band out of range"},
+		{r: 3, c: 3, kl: 0, ku: 3, want: "mat64: band out of range"},
+		{r: 3, c: -1, kl: 0, ku: 0, want: "mat64: negative dimension"},
+		{r: 3, c: 3, kl: -1, ku: 0, want: "mat64: negative dimension"},
+	} {
+		panicked, message := panics(func() { NewBandDense(test.r, test.c, test.kl, test.ku, test.data) })
+		if !panicked || message != test.want {
+			t.Errorf("unexpected panic for %d×%d kl=%d ku=%d: got: %q want: %q",
+				test.r, test.c, test.kl, test.ku, message, test.want)
+		}
+	}
+}
+
+func TestBandAtSet(t *testing.T) {
+	band := NewBandDense(4, 5, 1, 2, nil)
+	dense := NewDense(4, 5, nil)
+	var v float64
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 5; j++ {
+			if j < i-1 || i+2 < j {
+				panicked, message := panics(func() { band.SetBand(i, j, 1) })
+				if !panicked || message != matrix.ErrBandSet.Error() {
+					t.Errorf("unexpected panic for set outside band at (%d,%d): got: %q want: %q",
+						i, j, message, matrix.ErrBandSet)
+				}
+				continue
+			}
+			v++
+			band.SetBand(i, j, v)
+			dense.Set(i, j, v)
+		}
+	}
+	if !Equal(band, dense) {
+		t.Errorf("unexpected band matrix after set:\ngot:\n%v\nwant:\n%v", Formatted(band), Formatted(dense))
+	}
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 5; j++ {
+			if (j < i-1 || i+2 < j) && band.At(i, j) != 0 {
+				t.Errorf("unexpected non-zero value outside band at (%d,%d): %v", i, j, band.At(i, j))
+			}
+		}
+	}
+
+	for _, test := range []struct {
+		i, j int
+		want string
+	}{
+		{i: -1, j: 0, want: matrix.ErrRowAccess.Error()},
+		{i: 4, j: 0, want: matrix.ErrRowAccess.Error()},
+		{i: 0, j: -1, want: matrix.ErrColAccess.Error()},
+		{i: 0, j: 5, want: matrix.ErrColAccess.Error()},
+	} {
+		panicked, message := panics(func() { band.At(test.i, test.j) })
+		if !panicked || message != test.want {
+			t.Errorf("unexpected panic for At(%d,%d): got: %q want: %q", test.i, test.j, message, test.want)
+		}
+		panicked, message = panics(func() { band.SetBand(test.i, test.j, 0) })
+		if !panicked || message != test.want {
+			t.Errorf("unexpected panic for SetBand(%d,%d): got: %q want: %q", test.i, test.j, message, test.want)
+		}
+	}
+}
+
+func TestBandMulVec(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		r, c, kl, ku int
+	}{
+		{r: 1, c: 1, kl: 0, ku: 0},
+		{r: 5, c: 5, kl: 1, ku: 1},
+		{r: 6, c: 4, kl: 2, ku: 0},
+		{r: 4, c: 7, kl: 0, ku: 3},
+		{r: 8, c: 5, kl: 3, ku: 2},
+		{r: 9, c: 3, kl: 1, ku: 1},
+		{r: 3, c: 9, kl: 2, ku: 4},
+	} {
+		band := NewBandDense(test.r, test.c, test.kl, test.ku, nil)
+		for i := 0; i < test.r; i++ {
+			for j := max(0, i-test.kl); j < min(test.c, i+test.ku+1); j++ {
+				band.SetBand(i, j, rnd.NormFloat64())
+			}
+		}
+		dense := DenseCopyOf(band)
+
+		for _, trans := range []bool{false, true} {
+			var a, d Matrix = band, dense
+			n := test.c
+			if trans {
+				a, d = band.T(), dense.T()
+				n = test.r
+			}
+			x := NewVector(n, nil)
+			for i := 0; i < n; i++ {
+				x.SetVec(i, rnd.NormFloat64())
+			}
+			var got, want Vector
+			got.MulVec(a, x)
+			want.MulVec(d, x)
+			if !EqualApprox(&got, &want, 1e-14) {
+				t.Errorf("unexpected result for %d×%d kl=%d ku=%d trans=%t: got: %v want: %v",
+					test.r, test.c, test.kl, test.ku, trans, got.RawVector().Data, want.RawVector().Data)
+			}
+			if trans {
+				got.MulVec(band.TBand(), x)
+				if !EqualApprox(&got, &want, 1e-14) {
+					t.Errorf("unexpected result for TBand %d×%d kl=%d ku=%d: got: %v want: %v",
+						test.r, test.c, test.kl, test.ku, got.RawVector().Data, want.RawVector().Data)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/gonum/matrix"
+)
+
+// MulBatch computes dst[i] = a[i]·b[i] for each index of the batch. Nil
+// elements of dst are replaced by newly allocated matrices. The products are
+// computed concurrently across the batch dimension.
+//
+// MulBatch returns matrix.ErrSliceLengthMismatch if the lengths of dst, a and
+// b differ, and matrix.ErrShape if any a[i] and b[i] are not compatible for
+// multiplication or any non-zero dst[i] does not have the size of the product.
+// No products are computed when an error is returned.
+//
+// Each dst[i] may alias a[i] or b[i], but must not share storage with any
+// other element of the batch. MulBatch will panic before computing any
+// products if a non-nil dst[i] appears elsewhere in dst, a or b. A panic
+// while computing a product is propagated to the caller once all of the
+// concurrent computations have finished.
+func MulBatch(dst, a, b []*Dense) error {
+	return mulBatch(dst, a, b, runtime.GOMAXPROCS(0))
+}
+
+// mulBatch performs MulBatch using at most workers goroutines.
+func mulBatch(dst, a, b []*Dense, workers int) error {
+	if len(dst) != len(a) || len(a) != len(b) {
+		return matrix.ErrSliceLengthMismatch
+	}
+	for i := range a {
+		ar, ac := a[i].Dims()
+		br, bc := b[i].Dims()
+		if ac != br {
+			return matrix.ErrShape
+		}
+		if dst[i] != nil && !dst[i].isZero() {
+			if r, c := dst[i].Dims(); r != ar || c != bc {
+				return matrix.ErrShape
+			}
+		}
+	}
+	seen := make(map[*Dense]int, len(dst))
+	for i, d := range dst {
+		if d == nil {
+			continue
+		}
+		if _, ok := seen[d]; ok {
+			panic(regionIdentity)
+		}
+		seen[d] = i
+	}
+	for k := range a {
+		for _, v := range []*Dense{a[k], b[k]} {
+			if i, ok := seen[v]; ok && i != k {
+				panic(regionIdentity)
+			}
+		}
+	}
+	for i := range dst {
+		if dst[i] == nil {
+			dst[i] = &Dense{}
+		}
+	}
+
+	workers = min(workers, len(a))
+	if workers <= 1 {
+		for i := range a {
+			dst[i].Mul(a[i], b[i])
+		}
+		return nil
+	}
+	jobs := make(chan int)
+	var (
+		wg sync.WaitGroup
+
+		mu       sync.Mutex
+		panicked bool
+		pv       interface{}
+	)
+	mul := func(i int) {
+		// A panic in a worker cannot be recovered by the caller,
+		// so hold the first one to be raised again by mulBatch.
+		defer func() {
+			if r := recover(); r != nil {
+				mu.Lock()
+				if !panicked {
+					panicked = true
+					pv = r
+				}
+				mu.Unlock()
+			}
+		}()
+		dst[i].Mul(a[i], b[i])
+	}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				mul(i)
+			}
+		}()
+	}
+	for i := range a {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	if panicked {
+		panic(pv)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/gonum/matrix"
+)
+
+func TestMulBatch(t *testing.T) {
+	const batch = 17
+	a := make([]*Dense, batch)
+	b := make([]*Dense, batch)
+	for i := range a {
+		r, k, c := 1+rand.Intn(8), 1+rand.Intn(8), 1+rand.Intn(8)
+		a[i] = NewDense(r, k, nil)
+		b[i] = NewDense(k, c, nil)
+		for _, m := range []*Dense{a[i], b[i]} {
+			raw := m.RawMatrix()
+			for j := range raw.Data {
+				raw.Data[j] = rand.NormFloat64()
+			}
+		}
+	}
+
+	for _, workers := range []int{1, 4, batch + 1} {
+		dst := make([]*Dense, batch)
+		// Reuse a correctly sized receiver for one element.
+		dst[3] = NewDense(a[3].mat.Rows, b[3].mat.Cols, nil)
+		if err := mulBatch(dst, a, b, workers); err != nil {
+			t.Fatalf("unexpected error with %d workers: %v", workers, err)
+		}
+		for i := range dst {
+			var want Dense
+			want.Mul(a[i], b[i])
+			if !Equal(dst[i], &want) {
+				t.Errorf("unexpected product %d with %d workers:\ngot:\n%v\nwant:\n%v", i, workers, Formatted(dst[i]), Formatted(&want))
+			}
+		}
+	}
+
+	if err := MulBatch(make([]*Dense, 2), a[:2], b[:3]); err != matrix.ErrSliceLengthMismatch {
+		t.Errorf("unexpected error for mismatched batch lengths: got: %v want: %v", err, matrix.ErrSliceLengthMismatch)
+	}
+	bad := []*Dense{NewDense(2, 3, nil)}
+	if err := MulBatch(make([]*Dense, 1), bad, bad); err != matrix.ErrShape {
+		t.Errorf("unexpected error for incompatible operands: got: %v want: %v", err, matrix.ErrShape)
+	}
+	dst := []*Dense{NewDense(3, 3, nil)}
+	if err := MulBatch(dst, bad, []*Dense{NewDense(3, 2, nil)}); err != matrix.ErrShape {
+		t.Errorf("unexpected error for mis-sized receiver: got: %v want: %v", err, matrix.ErrShape)
+	}
+
+	// A failing batch must leave nil receivers untouched.
+	dst = make([]*Dense, 2)
+	ok := NewDense(2, 2, nil)
+	if err := MulBatch(dst, []*Dense{ok, bad[0]}, []*Dense{ok, bad[0]}); err != matrix.ErrShape {
+		t.Errorf("unexpected error for incompatible later operands: got: %v want: %v", err, matrix.ErrShape)
+	}
+	for i, d := range dst {
+		if d != nil {
+			t.Errorf("unexpected allocation of receiver %d on error", i)
+		}
+	}
+
+	// Receivers shared between batch elements are rejected, and a panic
+	// in a concurrent product is raised on the calling goroutine.
+	x, y := NewDense(2, 2, nil), NewDense(2, 2, nil)
+	big := NewDense(10, 10, nil)
+	for _, test := range []struct {
+		name    string
+		dst     []*Dense
+		a, b    []*Dense
+		want    string
+		workers int
+	}{
+		{
+			name: "duplicate receiver",
+			dst:  []*Dense{x, x}, a: []*Dense{ok, ok}, b: []*Dense{ok, ok},
+			want: regionIdentity, workers: 2,
+		},
+		{
+			name: "receiver is another element's operand",
+			dst:  []*Dense{x, y}, a: []*Dense{ok, x}, b: []*Dense{ok, ok},
+			want: regionIdentity, workers: 2,
+		},
+		{
+			name: "overlapping operand",
+			dst:  []*Dense{x, big.View(0, 0, 2, 2).(*Dense)}, a: []*Dense{ok, big.View(1, 1, 2, 2).(*Dense)}, b: []*Dense{ok, ok},
+			want: regionOverlap, workers: 2,
+		},
+	} {
+		panicked, message := panics(func() { mulBatch(test.dst, test.a, test.b, test.workers) })
+		if !panicked || message != test.want {
+			t.Errorf("unexpected panic for %s: got: %q want: %q", test.name, message, test.want)
+		}
+	}
+}
+
+func BenchmarkMulBatchSerial(b *testing.B)   { mulBatchBench(b, 64, 50, 1) }
+func BenchmarkMulBatchParallel(b *testing.B) { mulBatchBench(b, 64, 50, runtime.GOMAXPROCS(0)) }
+func mulBatchBench(b *testing.B, batch, size, workers int) {
+	b.StopTimer()
+	x := make([]*Dense, batch)
+	y := make([]*Dense, batch)
+	dst := make([]*Dense, batch)
+	for i := range x {
+		x[i], _ = randDense(size, 1, rand.NormFloat64)
+		y[i], _ = randDense(size, 1, rand.NormFloat64)
+	}
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		mulBatch(dst, x, y, workers)
+	}
+}
@@ -21,14 +21,19 @@ const badTriangle = "mat64: invalid triangle"
 type Cholesky struct {
 	chol *TriDense
 	cond float64
+
+	// valid is whether chol holds the factor of a
+	// positive definite matrix.
+	valid bool
 }
 
 // updateCond updates the condition number of the Cholesky decomposition. If
 // norm > 0, then that norm is used as the norm of the original matrix A, otherwise
-// the norm is estimated from the decompositon.
-func (c *Cholesky) updateCond(norm float64) {
+// the norm is estimated from the decompositon. Scratch memory is taken from
+// ws, which may be nil.
+func (c *Cholesky) updateCond(norm float64, ws *Workspace) {
 	n := c.chol.mat.N
-	work := make([]float64, 3*n)
+	work := ws.floats(3 * n)
 	if norm < 0 {
 		// This is an approximation. By the definition of a norm, ||AB|| <= ||A|| ||B||.
 		// Here, A = U^T * U.
@@ -41,7 +46,7 @@ func (c *Cholesky) updateCond(norm float64) {
 		norm = unorm * lnorm
 	}
 	sym := c.chol.asSymBlas()
-	iwork := make([]int, n)
+	iwork := ws.ints(n)
 	v := lapack64.Pocon(sym, norm, work, iwork)
 	c.cond = 1 / v
 }
@@ -49,26 +54,149 @@ func (c *Cholesky) updateCond(norm float64) {
 // Factorize calculates the Cholesky decomposition of the matrix A and returns
 // whether the matrix is positive definite.
 func (c *Cholesky) Factorize(a Symmetric) (ok bool) {
+	return c.FactorizeInto(a, nil)
+}
+
+// FactorizeInto calculates the Cholesky decomposition of the matrix A as
+// Factorize does, taking scratch memory from ws. When the receiver and ws
+// are reused for matrices of the same size, FactorizeInto does not allocate.
+// If ws is nil, scratch memory is allocated.
+func (c *Cholesky) FactorizeInto(a Symmetric, ws *Workspace) (ok bool) {
 	n := a.Symmetric()
-	if c.chol == nil {
+	switch {
+	case c.chol == nil:
 		c.chol = NewTriDense(n, true, nil)
-	} else {
+	case c.chol.mat.N != n:
 		c.chol = NewTriDense(n, true, use(c.chol.mat.Data, n*n))
 	}
 	copySymIntoTriangle(c.chol, a)
 
 	sym := c.chol.asSymBlas()
-	work := make([]float64, c.chol.mat.N)
+	work := ws.floats(c.chol.mat.N)
 	norm := lapack64.Lansy(matrix.CondNorm, sym, work)
 	_, ok = lapack64.Potrf(sym)
+	c.valid = ok
 	if ok {
-		c.updateCond(norm)
+		c.updateCond(norm, ws)
 	} else {
 		c.cond = math.Inf(1)
 	}
 	return ok
 }
 
+// ExtendVec computes the Cholesky factorization of the (n+1)×(n+1) matrix
+//  [ A   v ]
+//  [ v^T d ]
+// given the factorization of the n×n matrix A in orig, placing the result in
+// the receiver. ExtendVec returns whether the extended matrix is positive
+// definite. If it is not, the receiver is left unchanged. ExtendVec takes
+// O(n^2) time and panics if the length of v is not n.
+func (c *Cholesky) ExtendVec(orig *Cholesky, v *Vector, d float64) (ok bool) {
+	n := orig.Size()
+	if v.Len() != n {
+		panic(matrix.ErrShape)
+	}
+	// Writing the extended factor as
+	//  [ U w ]
+	//  [ 0 k ]
+	// gives U^T * w = v and k^2 = d - w^T * w.
+	w := NewVector(n, nil)
+	w.CopyVec(v)
+	blas64.Trsv(blas.Trans, orig.chol.mat, w.mat)
+	k2 := d - blas64.Dot(n, w.mat, w.mat)
+	if !(k2 > 0) {
+		return false
+	}
+
+	var chol *TriDense
+	if c.chol == nil || c == orig {
+		chol = NewTriDense(n+1, true, nil)
+	} else {
+		chol = NewTriDense(n+1, true, use(c.chol.mat.Data, (n+1)*(n+1)))
+	}
+	for i := 0; i < n; i++ {
+		copy(chol.mat.Data[i*chol.mat.Stride+i:i*chol.mat.Stride+n], orig.chol.mat.Data[i*orig.chol.mat.Stride+i:i*orig.chol.mat.Stride+n])
+		chol.mat.Data[i*chol.mat.Stride+n] = w.at(i)
+	}
+	chol.mat.Data[n*chol.mat.Stride+n] = math.Sqrt(k2)
+	c.chol = chol
+	c.valid = true
+	c.updateCond(-1, nil)
+	return true
+}
+
+// SymRankK computes the Cholesky factorization of
+//  A + alpha * X * X^T
+// given the factorization of A in orig, placing the result in the receiver.
+// The update is performed as a sequence of rank-one updates, one for each
+// column of x, and takes O(n^2 k) time for an n×k matrix x. If alpha is
+// negative the update is a downdate. SymRankK returns whether the updated
+// matrix is positive definite. If it is not, the receiver is left unchanged.
+// SymRankK panics if x does not have n rows.
+func (c *Cholesky) SymRankK(orig *Cholesky, alpha float64, x Matrix) (ok bool) {
+	n := orig.Size()
+	r, k := x.Dims()
+	if r != n {
+		panic(matrix.ErrShape)
+	}
+
+	// The update is performed in new storage so that the receiver
+	// is not modified if a downdate fails part way through.
+	chol := NewTriDense(n, true, nil)
+	chol.Copy(orig.chol)
+	if alpha == 0 || k == 0 {
+		c.chol = chol
+		c.cond = orig.cond
+		c.valid = orig.valid
+		return true
+	}
+
+	sign := 1.0
+	if alpha < 0 {
+		sign = -1
+	}
+	f := math.Sqrt(math.Abs(alpha))
+	col := make([]float64, n)
+	for j := 0; j < k; j++ {
+		Col(col, j, x)
+		for i := range col {
+			col[i] *= f
+		}
+		if !symRankOne(chol.mat, sign, col) {
+			return false
+		}
+	}
+	c.chol = chol
+	c.valid = true
+	c.updateCond(-1, nil)
+	return true
+}
+
+// symRankOne updates the upper triangular Cholesky factor u of A in place so
+// that it is the factor of A + sign * x * x^T, where sign is ±1. The contents
+// of x are overwritten. symRankOne returns false if the updated matrix is not
+// positive definite, in which case the contents of u are undefined.
+func symRankOne(u blas64.Triangular, sign float64, x []float64) (ok bool) {
+	n := u.N
+	for k := 0; k < n; k++ {
+		row := u.Data[k*u.Stride : k*u.Stride+n]
+		ukk := row[k]
+		r2 := ukk*ukk + sign*x[k]*x[k]
+		if !(r2 > 0) {
+			return false
+		}
+		r := math.Sqrt(r2)
+		c := r / ukk
+		s := x[k] / ukk
+		row[k] = r
+		for j := k + 1; j < n; j++ {
+			row[j] = (row[j] + sign*s*x[j]) / c
+			x[j] = c*x[j] - s*row[j]
+		}
+	}
+	return true
+}
+
 // Det returns the determinant of the matrix that has been factorized.
 func (c *Cholesky) Det() float64 {
 	return math.Exp(c.LogDet())
@@ -88,6 +216,21 @@ func (c *Cholesky) LogDet() float64 {
 	return det
 }
 
+// Residual returns the Frobenius norm of the residual
+//  ||A * x - b||
+// of a solution x of the system A * x = b, where a is the matrix that was
+// factorized. Residual is intended for checking the quality of a solution
+// found using SolveCholesky or SolveCholeskyVec. Residual will panic with
+// matrix.ErrShape if a is not the size of the factorized matrix, or if x and
+// b are not compatible with a.
+func (c *Cholesky) Residual(a, x, b Matrix) float64 {
+	r, cols := a.Dims()
+	if n := c.Size(); r != n || cols != n {
+		panic(matrix.ErrShape)
+	}
+	return residual(a, x, b)
+}
+
 // SolveCholesky finds the matrix m that solves A * m = b where A is represented
 // by the cholesky decomposition, placing the result in the receiver.
 func (m *Dense) SolveCholesky(chol *Cholesky, b Matrix) error {
@@ -180,6 +180,209 @@ func TestFromCholesky(t *testing.T) {
 	}
 }
 
+func TestCholeskyExtendVec(t *testing.T) {
+	for _, test := range []struct {
+		a    *SymDense
+		v    []float64
+		d    float64
+		want bool
+	}{
+		{
+			a: NewSymDense(3, []float64{
+				4, 1, 1,
+				0, 2, 3,
+				0, 0, 6,
+			}),
+			v:    []float64{1, 2, 0.5},
+			d:    10,
+			want: true,
+		},
+		{
+			a: NewSymDense(3, []float64{
+				53, 59, 37,
+				0, 83, 71,
+				0, 0, 101,
+			}),
+			v:    []float64{10, -3, 7},
+			d:    200,
+			want: true,
+		},
+		{
+			// The extended matrix is singular.
+			a:    NewSymDense(1, []float64{4}),
+			v:    []float64{2},
+			d:    1,
+			want: false,
+		},
+		{
+			a:    NewSymDense(2, []float64{2, 1, 1, 2}),
+			v:    []float64{3, 3},
+			d:    1,
+			want: false,
+		},
+	} {
+		n := test.a.Symmetric()
+		var orig Cholesky
+		if !orig.Factorize(test.a) {
+			t.Fatal("unexpected Cholesky factorization failure: not positive definite")
+		}
+
+		bordered := NewSymDense(n+1, nil)
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				bordered.SetSym(i, j, test.a.At(i, j))
+			}
+			bordered.SetSym(i, n, test.v[i])
+		}
+		bordered.SetSym(n, n, test.d)
+
+		var want Cholesky
+		ok := want.Factorize(bordered)
+		if ok != test.want {
+			t.Fatalf("unexpected positive definiteness of bordered matrix: got: %t want: %t", ok, test.want)
+		}
+
+		v := NewVector(n, test.v)
+		for _, chol := range []*Cholesky{
+			&Cholesky{},
+			&Cholesky{chol: NewTriDense(n+2, true, nil)},
+			&orig,
+		} {
+			var prev *TriDense
+			if chol.chol != nil {
+				prev = NewTriDense(chol.chol.mat.N, true, nil)
+				prev.Copy(chol.chol)
+			}
+			ok := chol.ExtendVec(&orig, v, test.d)
+			if ok != test.want {
+				t.Errorf("unexpected return from ExtendVec: got: ok=%t want: ok=%t", ok, test.want)
+			}
+			if !ok {
+				if prev != nil && !Equal(chol.chol, prev) {
+					t.Error("receiver modified by failed ExtendVec")
+				}
+				continue
+			}
+			if !EqualApprox(chol.chol, want.chol, 1e-12) {
+				t.Errorf("unexpected extended factor:\ngot:\n% v\nwant:\n% v", Formatted(chol.chol), Formatted(want.chol))
+			}
+			if math.Abs(chol.LogDet()-want.LogDet()) > 1e-12 {
+				t.Errorf("unexpected log determinant: got: %v want: %v", chol.LogDet(), want.LogDet())
+			}
+			var s SymDense
+			s.FromCholesky(chol)
+			if !EqualApprox(&s, bordered, 1e-12) {
+				t.Errorf("unexpected reconstruction:\ngot:\n% v\nwant:\n% v", Formatted(&s), Formatted(bordered))
+			}
+		}
+	}
+}
+
+func TestCholeskySymRankK(t *testing.T) {
+	for _, test := range []struct {
+		n, k  int
+		alpha float64
+	}{
+		{n: 1, k: 1, alpha: 1},
+		{n: 4, k: 1, alpha: 2},
+		{n: 5, k: 3, alpha: 0.5},
+		{n: 10, k: 4, alpha: -0.1},
+		{n: 10, k: 12, alpha: 1},
+		{n: 6, k: 2, alpha: 0},
+	} {
+		n, k := test.n, test.k
+		// Construct a well conditioned positive definite matrix.
+		b := NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				b.Set(i, j, rand.NormFloat64())
+			}
+		}
+		a := NewSymDense(n, nil)
+		a.SymOuterK(1, b)
+		for i := 0; i < n; i++ {
+			a.SetSym(i, i, a.At(i, i)+float64(n))
+		}
+		x := NewDense(n, k, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < k; j++ {
+				x.Set(i, j, rand.Float64()-0.5)
+			}
+		}
+
+		var orig Cholesky
+		if !orig.Factorize(a) {
+			t.Fatalf("unexpected Cholesky factorization failure for n=%d", n)
+		}
+
+		updated := NewSymDense(n, nil)
+		updated.SymRankK(a, test.alpha, x)
+		var want Cholesky
+		if !want.Factorize(updated) {
+			t.Fatalf("unexpected Cholesky factorization failure of updated matrix for n=%d", n)
+		}
+
+		for _, chol := range []*Cholesky{
+			&Cholesky{},
+			&Cholesky{chol: NewTriDense(n+1, true, nil)},
+			&orig,
+		} {
+			ok := chol.SymRankK(&orig, test.alpha, x)
+			if !ok {
+				t.Errorf("unexpected SymRankK failure for n=%d, k=%d, alpha=%v", n, k, test.alpha)
+				continue
+			}
+			if !EqualApprox(chol.chol, want.chol, 1e-10) {
+				t.Errorf("unexpected updated factor for n=%d, k=%d, alpha=%v:\ngot:\n% v\nwant:\n% v",
+					n, k, test.alpha, Formatted(chol.chol), Formatted(want.chol))
+			}
+			var s SymDense
+			s.FromCholesky(chol)
+			if !EqualApprox(&s, updated, 1e-10) {
+				t.Errorf("unexpected reconstruction for n=%d, k=%d, alpha=%v", n, k, test.alpha)
+			}
+		}
+	}
+
+	// Removing a direction that the matrix does not contain makes it indefinite.
+	a := NewSymDense(2, []float64{
+		2, 0,
+		0, 1,
+	})
+	var orig Cholesky
+	if !orig.Factorize(a) {
+		t.Fatal("unexpected Cholesky factorization failure")
+	}
+	// The receiver holds the factorization of a different
+	// matrix which must survive the failed downdate.
+	chol := &Cholesky{}
+	if !chol.Factorize(NewSymDense(2, []float64{
+		9, 1,
+		1, 9,
+	})) {
+		t.Fatal("unexpected Cholesky factorization failure")
+	}
+	prev := NewTriDense(2, true, nil)
+	prev.Copy(chol.chol)
+	prevCond := chol.cond
+	x := NewDense(2, 1, []float64{0, 2})
+	if chol.SymRankK(&orig, -1, x) {
+		t.Error("unexpected success downdating to an indefinite matrix")
+	}
+	if !Equal(chol.chol, prev) || chol.cond != prevCond {
+		t.Errorf("receiver modified by failed SymRankK: got:\n% v\nwant:\n% v", Formatted(chol.chol), Formatted(prev))
+	}
+
+	// A failed in place downdate must leave orig unchanged.
+	prev.Copy(orig.chol)
+	if orig.SymRankK(&orig, -1, x) {
+		t.Error("unexpected success downdating to an indefinite matrix in place")
+	}
+	if !Equal(orig.chol, prev) {
+		t.Error("orig modified by failed in place SymRankK")
+	}
+}
+
 func BenchmarkCholeskySmall(b *testing.B) {
 	benchmarkCholesky(b, 2)
 }
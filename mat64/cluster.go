@@ -0,0 +1,91 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/gonum/floats"
+	"github.com/gonum/matrix"
+)
+
+// KMeansPlusPlus returns a k×c matrix of initial centroids for k-means
+// clustering of the rows of the r×c matrix x, chosen by the k-means++
+// strategy. The first centroid is a row chosen uniformly at random and each
+// subsequent centroid is a row chosen with probability proportional to its
+// squared Euclidean distance from the nearest centroid already chosen. If
+// all remaining rows coincide with chosen centroids, the next centroid is
+// chosen uniformly from the rows not yet chosen.
+//
+// Random numbers are drawn from rng, or from the math/rand package's global
+// source if rng is nil. KMeansPlusPlus will panic with matrix.ErrShape if k
+// is not positive or is greater than r.
+func KMeansPlusPlus(x Matrix, k int, rng *rand.Rand) *Dense {
+	r, c := x.Dims()
+	if k <= 0 || k > r {
+		panic(matrix.ErrShape)
+	}
+	intn, float := rand.Intn, rand.Float64
+	if rng != nil {
+		intn, float = rng.Intn, rng.Float64
+	}
+
+	rows := make([][]float64, r)
+	for i := range rows {
+		rows[i] = Row(nil, i, x)
+	}
+	chosen := make([]bool, r)
+	dist := make([]float64, r)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+
+	centroids := NewDense(k, c, nil)
+	next := intn(r)
+	for n := 0; ; n++ {
+		chosen[next] = true
+		copy(centroids.rowView(n), rows[next])
+		if n == k-1 {
+			return centroids
+		}
+
+		for i, row := range rows {
+			d := floats.Distance(row, rows[next], 2)
+			dist[i] = math.Min(dist[i], d*d)
+		}
+		total := floats.Sum(dist)
+		if total == 0 {
+			// Every row coincides with a centroid, so choose
+			// uniformly among the rows not yet chosen.
+			skip := intn(r - n - 1)
+			for i, done := range chosen {
+				if done {
+					continue
+				}
+				if skip == 0 {
+					next = i
+					break
+				}
+				skip--
+			}
+			continue
+		}
+		target := float() * total
+		next = r - 1
+		for i, d := range dist {
+			target -= d
+			if target < 0 {
+				next = i
+				break
+			}
+		}
+		// Rounding may leave the target unreached; never
+		// select a row with zero weight.
+		for dist[next] == 0 {
+			next--
+		}
+	}
+}
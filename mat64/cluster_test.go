@@ -0,0 +1,76 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/gonum/matrix"
+)
+
+func TestKMeansPlusPlus(t *testing.T) {
+	// Three tight, well separated clusters of ten points each.
+	centers := [][]float64{{0, 0}, {100, 0}, {0, 100}}
+	rnd := rand.New(rand.NewSource(1))
+	x := NewDense(30, 2, nil)
+	for i := 0; i < 30; i++ {
+		c := centers[i%3]
+		x.Set(i, 0, c[0]+rnd.NormFloat64())
+		x.Set(i, 1, c[1]+rnd.NormFloat64())
+	}
+
+	for seed := int64(0); seed < 10; seed++ {
+		got := KMeansPlusPlus(x, 3, rand.New(rand.NewSource(seed)))
+		if r, c := got.Dims(); r != 3 || c != 2 {
+			t.Fatalf("unexpected centroid dimensions: got: %d×%d want: 3×2", r, c)
+		}
+		clusters := make(map[int]bool)
+		for n := 0; n < 3; n++ {
+			centroid := got.RawRowView(n)
+			row := -1
+			for i := 0; i < 30; i++ {
+				if floats.Equal(centroid, x.RawRowView(i)) {
+					row = i
+				}
+			}
+			if row < 0 {
+				t.Errorf("centroid %d is not an input row for seed %d: %v", n, seed, centroid)
+				continue
+			}
+			clusters[row%3] = true
+		}
+		// The distance weighting must spread the centroids
+		// across the clusters.
+		if len(clusters) != 3 {
+			t.Errorf("centroids not spread across clusters for seed %d:\n%v", seed, Formatted(got))
+		}
+
+		// The same source must give the same centroids.
+		if again := KMeansPlusPlus(x, 3, rand.New(rand.NewSource(seed))); !Equal(got, again) {
+			t.Errorf("centroids not reproducible for seed %d", seed)
+		}
+	}
+
+	// When there are fewer distinct rows than centroids,
+	// the remaining rows are chosen without repetition.
+	dup := NewDense(4, 1, []float64{1, 1, 1, 2})
+	got := KMeansPlusPlus(dup, 4, rand.New(rand.NewSource(1)))
+	var sum float64
+	for i := 0; i < 4; i++ {
+		sum += got.At(i, 0)
+	}
+	if sum != 5 {
+		t.Errorf("unexpected centroids for duplicated rows: got: %v", Col(nil, 0, got))
+	}
+
+	for _, k := range []int{0, 31} {
+		panicked, message := panics(func() { KMeansPlusPlus(x, k, nil) })
+		if !panicked || message != matrix.ErrShape.Error() {
+			t.Errorf("expected shape panic for k=%d", k)
+		}
+	}
+}
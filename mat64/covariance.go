@@ -0,0 +1,76 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import "github.com/gonum/matrix"
+
+// CovAccumulator computes the sample covariance of a stream of observations
+// in a single pass without storing the observations. The running mean and the
+// sum of squared deviations from it are updated with each observation using
+// Welford's method, which avoids the cancellation of the textbook formula
+// based on sums of squares.
+//
+// The zero value of CovAccumulator is ready to use. The dimension of the
+// observations is set by the first call to Add.
+type CovAccumulator struct {
+	n     int
+	mean  *Vector
+	delta *Vector
+	// m2 holds the sum of outer products
+	// of the deviations from the mean.
+	m2 *SymDense
+}
+
+// Add adds the observation x to the accumulator. Add will panic with
+// matrix.ErrShape if the length of x differs from that of the observations
+// already added.
+func (c *CovAccumulator) Add(x *Vector) {
+	d := x.Len()
+	if c.n == 0 {
+		c.mean = NewVector(d, nil)
+		c.delta = NewVector(d, nil)
+		c.m2 = NewSymDense(d, nil)
+	} else if d != c.mean.Len() {
+		panic(matrix.ErrShape)
+	}
+
+	c.n++
+	c.delta.SubVec(x, c.mean)
+	c.mean.AddScaledVec(c.mean, 1/float64(c.n), c.delta)
+	// With delta' = x - mean after the update, the sum of squares
+	// is updated by delta * delta'^T = (n-1)/n * delta * delta^T.
+	c.m2.SymRankOne(c.m2, float64(c.n-1)/float64(c.n), c.delta)
+}
+
+// Count returns the number of observations added to the accumulator.
+func (c *CovAccumulator) Count() int {
+	return c.n
+}
+
+// MeanTo places the mean of the observations added to the accumulator
+// into dst. MeanTo will panic with matrix.ErrZeroLength if no observations
+// have been added, and with matrix.ErrShape if dst is not zero and does not
+// match the dimension of the observations.
+func (c *CovAccumulator) MeanTo(dst *Vector) {
+	if c.n == 0 {
+		panic(matrix.ErrZeroLength)
+	}
+	dst.reuseAs(c.mean.Len())
+	dst.CopyVec(c.mean)
+}
+
+// CovarianceTo places the unbiased sample covariance of the observations
+// added to the accumulator into dst, dividing the sums of squared deviations
+// by n-1 for n observations. If only one observation has been added, the
+// elements of the covariance are NaN. CovarianceTo will panic with
+// matrix.ErrZeroLength if no observations have been added, and with
+// matrix.ErrShape if dst is not zero and does not match the dimension of
+// the observations.
+func (c *CovAccumulator) CovarianceTo(dst *SymDense) {
+	if c.n == 0 {
+		panic(matrix.ErrZeroLength)
+	}
+	dst.ScaleSym(1/float64(c.n-1), c.m2)
+}
@@ -0,0 +1,109 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/matrix"
+)
+
+// batchCovariance returns the unbiased sample covariance of the rows
+// of x computed by the two-pass algorithm.
+func batchCovariance(x *Dense) *SymDense {
+	r, c := x.Dims()
+	var mean Vector
+	mean.MeanVec(x, nil)
+	centered := NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			centered.Set(i, j, x.At(i, j)-mean.At(j, 0))
+		}
+	}
+	var cov SymDense
+	cov.SymOuterK(1/float64(r-1), centered.T())
+	return &cov
+}
+
+func TestCovAccumulator(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		r, c   int
+		offset float64
+		tol    float64
+	}{
+		{r: 2, c: 1, tol: 1e-14},
+		{r: 10, c: 3, tol: 1e-14},
+		{r: 500, c: 5, tol: 1e-13},
+		// A large offset causes catastrophic cancellation in the
+		// naive sum of squares formula, but not in Welford's method.
+		{r: 500, c: 4, offset: 1e8, tol: 1e-7},
+	} {
+		x := NewDense(test.r, test.c, nil)
+		for i := 0; i < test.r; i++ {
+			for j := 0; j < test.c; j++ {
+				x.Set(i, j, test.offset+float64(j+1)*rnd.NormFloat64())
+			}
+		}
+
+		var acc CovAccumulator
+		for i := 0; i < test.r; i++ {
+			acc.Add(x.RowView(i))
+		}
+		if n := acc.Count(); n != test.r {
+			t.Errorf("unexpected count: got: %d want: %d", n, test.r)
+		}
+
+		var got SymDense
+		acc.CovarianceTo(&got)
+		want := batchCovariance(x)
+		if !EqualApprox(&got, want, test.tol) {
+			t.Errorf("unexpected covariance for %d×%d data with offset %v:\ngot:\n%v\nwant:\n%v",
+				test.r, test.c, test.offset, Formatted(&got), Formatted(want))
+		}
+
+		var mean, wantMean Vector
+		acc.MeanTo(&mean)
+		wantMean.MeanVec(x, nil)
+		if !EqualApprox(&mean, &wantMean, 1e-14) {
+			t.Errorf("unexpected mean for %d×%d data with offset %v: got: %v want: %v",
+				test.r, test.c, test.offset, mean.RawVector().Data, wantMean.RawVector().Data)
+		}
+
+		// A correctly sized receiver is overwritten.
+		acc.CovarianceTo(&got)
+		if !EqualApprox(&got, want, test.tol) {
+			t.Errorf("unexpected covariance into reused receiver for %d×%d data", test.r, test.c)
+		}
+	}
+
+	var acc CovAccumulator
+	panicked, message := panics(func() { acc.CovarianceTo(&SymDense{}) })
+	if !panicked || message != matrix.ErrZeroLength.Error() {
+		t.Errorf("unexpected panic for empty accumulator: got: %q want: %q", message, matrix.ErrZeroLength)
+	}
+
+	acc.Add(NewVector(2, []float64{1, 2}))
+	var cov SymDense
+	acc.CovarianceTo(&cov)
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			if v := cov.At(i, j); !math.IsNaN(v) {
+				t.Errorf("unexpected covariance element (%d,%d) for single observation: got: %v want: NaN", i, j, v)
+			}
+		}
+	}
+
+	panicked, message = panics(func() { acc.Add(NewVector(3, nil)) })
+	if !panicked || message != matrix.ErrShape.Error() {
+		t.Errorf("unexpected panic for mismatched observation: got: %q want: %q", message, matrix.ErrShape)
+	}
+	panicked, message = panics(func() { acc.CovarianceTo(NewSymDense(3, nil)) })
+	if !panicked || message != matrix.ErrShape.Error() {
+		t.Errorf("unexpected panic for mismatched receiver: got: %q want: %q", message, matrix.ErrShape)
+	}
+}
@@ -0,0 +1,404 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"sort"
+
+	"github.com/gonum/matrix"
+)
+
+// defaultCompressThreshold is the density below which Compress
+// returns a sparse matrix when no threshold is given.
+const defaultCompressThreshold = 0.1
+
+var (
+	csr *CSR
+
+	_ Matrix         = csr
+	_ NonZeroCounter = csr
+
+	csc *CSC
+
+	_ Matrix         = csc
+	_ NonZeroCounter = csc
+	_ Untransposer   = csc
+)
+
+// CSR is a sparse matrix in compressed sparse row format. The column indices
+// and values of the stored elements of row i are held in ind[indptr[i]:indptr[i+1]]
+// and data[indptr[i]:indptr[i+1]], with the column indices strictly increasing
+// within each row. Elements that are not stored are zero.
+type CSR struct {
+	rows, cols int
+
+	indptr []int
+	ind    []int
+	data   []float64
+}
+
+// NewCSR creates a new r×c matrix of type CSR from the compressed sparse row
+// representation described by indptr, ind and data. The slices are used as the
+// backing data of the returned matrix and must not be modified afterwards.
+//
+// NewCSR will panic with matrix.ErrZeroLength if r or c is not positive, with
+// matrix.ErrShape if len(indptr) is not r+1, indptr[0] is not zero, indptr is
+// decreasing or indptr[r] does not equal len(ind) and len(data), and with
+// matrix.ErrColAccess if the column indices of a row are out of range or are
+// not strictly increasing.
+func NewCSR(r, c int, indptr, ind []int, data []float64) *CSR {
+	checkCompressed(r, c, indptr, ind, data, matrix.ErrColAccess)
+	return &CSR{
+		rows:   r,
+		cols:   c,
+		indptr: indptr,
+		ind:    ind,
+		data:   data,
+	}
+}
+
+// checkCompressed checks that indptr, ind and data describe a valid compressed
+// structure with n compressed vectors of length l, panicking with accessErr if
+// the indices of a vector are out of range or not strictly increasing.
+func checkCompressed(n, l int, indptr, ind []int, data []float64, accessErr matrix.Error) {
+	if n <= 0 || l <= 0 {
+		panic(matrix.ErrZeroLength)
+	}
+	if len(indptr) != n+1 || indptr[0] != 0 || indptr[n] != len(ind) || len(ind) != len(data) {
+		panic(matrix.ErrShape)
+	}
+	for i := 0; i < n; i++ {
+		if indptr[i] > indptr[i+1] {
+			panic(matrix.ErrShape)
+		}
+		prev := -1
+		for _, j := range ind[indptr[i]:indptr[i+1]] {
+			if j <= prev || j >= l {
+				panic(accessErr)
+			}
+			prev = j
+		}
+	}
+}
+
+// NewCSRFrom returns a new CSR holding the non-zero elements of a.
+func NewCSRFrom(a Matrix) *CSR {
+	r, c := a.Dims()
+	m := &CSR{
+		rows:   r,
+		cols:   c,
+		indptr: make([]int, r+1),
+	}
+	row := make([]float64, c)
+	for i := 0; i < r; i++ {
+		for j, v := range Row(row, i, a) {
+			if v != 0 {
+				m.ind = append(m.ind, j)
+				m.data = append(m.data, v)
+			}
+		}
+		m.indptr[i+1] = len(m.ind)
+	}
+	return m
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *CSR) Dims() (r, c int) { return m.rows, m.cols }
+
+// At returns the element at row i, column j.
+func (m *CSR) At(i, j int) float64 {
+	if i >= m.rows || i < 0 {
+		panic(matrix.ErrRowAccess)
+	}
+	if j >= m.cols || j < 0 {
+		panic(matrix.ErrColAccess)
+	}
+	return compressedAt(m.indptr, m.ind, m.data, i, j)
+}
+
+// compressedAt returns the element at index j of the ith compressed vector.
+func compressedAt(indptr, ind []int, data []float64, i, j int) float64 {
+	lo, hi := indptr[i], indptr[i+1]
+	k := lo + sort.SearchInts(ind[lo:hi], j)
+	if k < hi && ind[k] == j {
+		return data[k]
+	}
+	return 0
+}
+
+// T returns the transpose of the receiver as a CSC sharing the receiver's
+// backing data.
+func (m *CSR) T() Matrix {
+	return &CSC{
+		rows:   m.cols,
+		cols:   m.rows,
+		indptr: m.indptr,
+		ind:    m.ind,
+		data:   m.data,
+	}
+}
+
+// DoNonZero calls fn for each of the stored elements of the matrix in
+// row-major order.
+func (m *CSR) DoNonZero(fn func(i, j int, v float64)) {
+	for i := 0; i < m.rows; i++ {
+		for k := m.indptr[i]; k < m.indptr[i+1]; k++ {
+			fn(i, m.ind[k], m.data[k])
+		}
+	}
+}
+
+// NNZ returns the number of stored elements of the matrix. Stored elements
+// are counted even if their value is zero.
+func (m *CSR) NNZ() int {
+	return len(m.data)
+}
+
+// Add computes the sum of a and b, placing the result in the receiver. The
+// stored elements of the result are the union of the stored elements of a
+// and b, including elements whose sum is zero. Any previous contents of the
+// receiver are replaced by a newly allocated structure, so the receiver may
+// be a or b.
+//
+// Add returns matrix.ErrShape if a and b do not have the same dimensions,
+// leaving the receiver unmodified.
+func (m *CSR) Add(a, b *CSR) error {
+	if a.rows != b.rows || a.cols != b.cols {
+		return matrix.ErrShape
+	}
+
+	n := len(a.data) + len(b.data)
+	indptr := make([]int, a.rows+1)
+	ind := make([]int, 0, n)
+	data := make([]float64, 0, n)
+	for i := 0; i < a.rows; i++ {
+		ka, kb := a.indptr[i], b.indptr[i]
+		for ka < a.indptr[i+1] || kb < b.indptr[i+1] {
+			switch {
+			case kb == b.indptr[i+1] || (ka < a.indptr[i+1] && a.ind[ka] < b.ind[kb]):
+				ind = append(ind, a.ind[ka])
+				data = append(data, a.data[ka])
+				ka++
+			case ka == a.indptr[i+1] || b.ind[kb] < a.ind[ka]:
+				ind = append(ind, b.ind[kb])
+				data = append(data, b.data[kb])
+				kb++
+			default:
+				ind = append(ind, a.ind[ka])
+				data = append(data, a.data[ka]+b.data[kb])
+				ka++
+				kb++
+			}
+		}
+		indptr[i+1] = len(ind)
+	}
+	*m = CSR{
+		rows:   a.rows,
+		cols:   a.cols,
+		indptr: indptr,
+		ind:    ind,
+		data:   data,
+	}
+	return nil
+}
+
+// ILU0 computes the incomplete LU factorization of a with zero fill-in,
+// returning a unit lower triangular l and an upper triangular u such that the
+// elements of l·u equal those of a at the stored positions of a. The factors
+// retain the sparsity pattern of a, with the unit diagonal of l stored
+// explicitly, and are suitable for use as a preconditioner in iterative
+// solvers.
+//
+// ILU0 returns matrix.ErrSquare if a is not square and matrix.ErrSingular if
+// a zero pivot is encountered, including when a diagonal element of a is not
+// stored.
+func ILU0(a *CSR) (l, u *CSR, err error) {
+	n := a.rows
+	if a.cols != n {
+		return nil, nil, matrix.ErrSquare
+	}
+
+	// diag holds the position of the diagonal element of each row.
+	diag := make([]int, n)
+	for i := range diag {
+		lo, hi := a.indptr[i], a.indptr[i+1]
+		k := lo + sort.SearchInts(a.ind[lo:hi], i)
+		if k == hi || a.ind[k] != i {
+			return nil, nil, matrix.ErrSingular
+		}
+		diag[i] = k
+	}
+
+	w := make([]float64, len(a.data))
+	copy(w, a.data)
+	for i := 0; i < n; i++ {
+		for p := a.indptr[i]; p < diag[i]; p++ {
+			k := a.ind[p]
+			if w[diag[k]] == 0 {
+				return nil, nil, matrix.ErrSingular
+			}
+			w[p] /= w[diag[k]]
+			// Update the remaining elements of row i that are
+			// in the pattern of both row i and row k.
+			q := p + 1
+			for r := diag[k] + 1; r < a.indptr[k+1]; r++ {
+				for q < a.indptr[i+1] && a.ind[q] < a.ind[r] {
+					q++
+				}
+				if q == a.indptr[i+1] {
+					break
+				}
+				if a.ind[q] == a.ind[r] {
+					w[q] -= w[p] * w[r]
+				}
+			}
+		}
+		if w[diag[i]] == 0 {
+			return nil, nil, matrix.ErrSingular
+		}
+	}
+
+	l = &CSR{rows: n, cols: n, indptr: make([]int, n+1)}
+	u = &CSR{rows: n, cols: n, indptr: make([]int, n+1)}
+	for i := 0; i < n; i++ {
+		l.ind = append(l.ind, a.ind[a.indptr[i]:diag[i]]...)
+		l.data = append(l.data, w[a.indptr[i]:diag[i]]...)
+		l.ind = append(l.ind, i)
+		l.data = append(l.data, 1)
+		l.indptr[i+1] = len(l.ind)
+
+		u.ind = append(u.ind, a.ind[diag[i]:a.indptr[i+1]]...)
+		u.data = append(u.data, w[diag[i]:a.indptr[i+1]]...)
+		u.indptr[i+1] = len(u.ind)
+	}
+	return l, u, nil
+}
+
+// mulVecTo computes dst = m·x, or dst = mᵀ·x if trans is true, visiting
+// only the stored elements of m. dst must have the length of the result and
+// must not alias x.
+func (m *CSR) mulVecTo(dst *Vector, trans bool, x *Vector) {
+	if trans {
+		for i := 0; i < dst.Len(); i++ {
+			dst.mat.Data[i*dst.mat.Inc] = 0
+		}
+		for i := 0; i < m.rows; i++ {
+			xi := x.mat.Data[i*x.mat.Inc]
+			if xi == 0 {
+				continue
+			}
+			for k := m.indptr[i]; k < m.indptr[i+1]; k++ {
+				dst.mat.Data[m.ind[k]*dst.mat.Inc] += m.data[k] * xi
+			}
+		}
+		return
+	}
+	for i := 0; i < m.rows; i++ {
+		var sum float64
+		for k := m.indptr[i]; k < m.indptr[i+1]; k++ {
+			sum += m.data[k] * x.mat.Data[m.ind[k]*x.mat.Inc]
+		}
+		dst.mat.Data[i*dst.mat.Inc] = sum
+	}
+}
+
+// CSC is a sparse matrix in compressed sparse column format. The row indices
+// and values of the stored elements of column j are held in
+// ind[indptr[j]:indptr[j+1]] and data[indptr[j]:indptr[j+1]], with the row
+// indices strictly increasing within each column. Elements that are not
+// stored are zero.
+//
+// A CSC has the same representation as the CSR of its transpose, and
+// transposing between the two formats does not copy the backing data.
+type CSC struct {
+	rows, cols int
+
+	indptr []int
+	ind    []int
+	data   []float64
+}
+
+// NewCSC creates a new r×c matrix of type CSC from the compressed sparse column
+// representation described by indptr, ind and data. The slices are used as the
+// backing data of the returned matrix and must not be modified afterwards.
+//
+// NewCSC will panic with matrix.ErrZeroLength if r or c is not positive, with
+// matrix.ErrShape if len(indptr) is not c+1, indptr[0] is not zero, indptr is
+// decreasing or indptr[c] does not equal len(ind) and len(data), and with
+// matrix.ErrRowAccess if the row indices of a column are out of range or are
+// not strictly increasing.
+func NewCSC(r, c int, indptr, ind []int, data []float64) *CSC {
+	checkCompressed(c, r, indptr, ind, data, matrix.ErrRowAccess)
+	return &CSC{
+		rows:   r,
+		cols:   c,
+		indptr: indptr,
+		ind:    ind,
+		data:   data,
+	}
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *CSC) Dims() (r, c int) { return m.rows, m.cols }
+
+// At returns the element at row i, column j.
+func (m *CSC) At(i, j int) float64 {
+	if i >= m.rows || i < 0 {
+		panic(matrix.ErrRowAccess)
+	}
+	if j >= m.cols || j < 0 {
+		panic(matrix.ErrColAccess)
+	}
+	return compressedAt(m.indptr, m.ind, m.data, j, i)
+}
+
+// T returns the transpose of the receiver as a CSR sharing the receiver's
+// backing data.
+func (m *CSC) T() Matrix {
+	return m.Untranspose()
+}
+
+// Untranspose returns the transpose of the receiver as a CSR sharing the
+// receiver's backing data. This allows operations that handle transposed
+// CSR matrices to handle a CSC without copying.
+func (m *CSC) Untranspose() Matrix {
+	return &CSR{
+		rows:   m.cols,
+		cols:   m.rows,
+		indptr: m.indptr,
+		ind:    m.ind,
+		data:   m.data,
+	}
+}
+
+// NNZ returns the number of stored elements of the matrix. Stored elements
+// are counted even if their value is zero.
+func (m *CSC) NNZ() int {
+	return len(m.data)
+}
+
+// DoNonZero calls fn for each of the stored elements of the matrix in
+// column-major order.
+func (m *CSC) DoNonZero(fn func(i, j int, v float64)) {
+	for j := 0; j < m.cols; j++ {
+		for k := m.indptr[j]; k < m.indptr[j+1]; k++ {
+			fn(m.ind[k], j, m.data[k])
+		}
+	}
+}
+
+// Compress returns a copy of a in a storage format suited to its density.
+// If the density of a, as returned by Density, is below threshold, the copy
+// is a *CSR, otherwise it is a *Dense. If threshold is not positive, a
+// threshold of 0.1 is used.
+func Compress(a Matrix, threshold float64) Matrix {
+	if threshold <= 0 {
+		threshold = defaultCompressThreshold
+	}
+	if Density(a) < threshold {
+		return NewCSRFrom(a)
+	}
+	return DenseCopyOf(a)
+}
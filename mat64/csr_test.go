@@ -0,0 +1,403 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/matrix"
+)
+
+func TestNewCSR(t *testing.T) {
+	want := NewDense(3, 4, []float64{
+		1, 0, 0, 2,
+		0, 0, 0, 0,
+		0, 3, 4, 0,
+	})
+	m := NewCSR(3, 4, []int{0, 2, 2, 4}, []int{0, 3, 1, 2}, []float64{1, 2, 3, 4})
+	if r, c := m.Dims(); r != 3 || c != 4 {
+		t.Errorf("unexpected dimensions: got: %d×%d want: 3×4", r, c)
+	}
+	if !Equal(m, want) {
+		t.Errorf("unexpected matrix:\ngot:\n%v\nwant:\n%v", Formatted(m), Formatted(want))
+	}
+	if !Equal(m.T(), want.T()) {
+		t.Errorf("unexpected transpose:\ngot:\n%v\nwant:\n%v", Formatted(m.T()), Formatted(want.T()))
+	}
+	if n := m.NNZ(); n != 4 {
+		t.Errorf("unexpected number of stored elements: got: %d want: 4", n)
+	}
+
+	from := NewCSRFrom(want)
+	if !Equal(from, want) || from.NNZ() != 4 {
+		t.Errorf("unexpected matrix from dense:\ngot:\n%v\nwant:\n%v", Formatted(from), Formatted(want))
+	}
+
+	for _, test := range []struct {
+		r, c   int
+		indptr []int
+		ind    []int
+		data   []float64
+		err    matrix.Error
+	}{
+		{r: 0, c: 2, indptr: []int{0}, err: matrix.ErrZeroLength},
+		{r: 2, c: 2, indptr: []int{0, 1}, ind: []int{0}, data: []float64{1}, err: matrix.ErrShape},
+		{r: 2, c: 2, indptr: []int{1, 1, 1}, ind: []int{0}, data: []float64{1}, err: matrix.ErrShape},
+		{r: 2, c: 2, indptr: []int{0, 1, 2}, ind: []int{0}, data: []float64{1}, err: matrix.ErrShape},
+		{r: 2, c: 2, indptr: []int{0, 2, 1}, ind: []int{0, 1}, data: []float64{1, 2}, err: matrix.ErrShape},
+		{r: 2, c: 2, indptr: []int{0, 1, 2}, ind: []int{0, 1}, data: []float64{1}, err: matrix.ErrShape},
+		{r: 2, c: 2, indptr: []int{0, 2, 2}, ind: []int{1, 0}, data: []float64{1, 2}, err: matrix.ErrColAccess},
+		{r: 2, c: 2, indptr: []int{0, 1, 2}, ind: []int{0, 2}, data: []float64{1, 2}, err: matrix.ErrColAccess},
+	} {
+		panicked, message := panics(func() { NewCSR(test.r, test.c, test.indptr, test.ind, test.data) })
+		if !panicked || message != test.err.Error() {
+			t.Errorf("unexpected panic for %+v: got: %q want: %q", test, message, test.err)
+		}
+	}
+}
+
+func TestCompress(t *testing.T) {
+	sparse := NewDense(10, 10, nil)
+	for i := 0; i < 10; i += 3 {
+		sparse.Set(i, (i*7)%10, float64(i+1))
+	}
+	dense := NewDense(2, 2, []float64{1, 2, 0, 4})
+
+	for _, test := range []struct {
+		a         Matrix
+		threshold float64
+		csr       bool
+	}{
+		{a: sparse, threshold: 0, csr: true},
+		{a: sparse, threshold: 0.5, csr: true},
+		{a: sparse, threshold: 0.01, csr: false},
+		{a: dense, threshold: 0, csr: false},
+		{a: dense, threshold: 0.8, csr: true},
+	} {
+		got := Compress(test.a, test.threshold)
+		if _, ok := got.(*CSR); ok != test.csr {
+			t.Errorf("unexpected storage for density %v and threshold %v: got: %T", Density(test.a), test.threshold, got)
+		}
+		if _, ok := got.(*Dense); ok == test.csr {
+			t.Errorf("unexpected storage for density %v and threshold %v: got: %T", Density(test.a), test.threshold, got)
+		}
+		if !Equal(got, test.a) {
+			t.Errorf("unexpected compressed matrix:\ngot:\n%v\nwant:\n%v", Formatted(got), Formatted(test.a))
+		}
+	}
+}
+
+// randCSR returns a random r×c CSR with approximately nnzPerRow stored
+// elements in each row, and its dense equivalent.
+func randCSR(r, c, nnzPerRow int, rnd func() float64) (*CSR, *Dense) {
+	d := NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for k := 0; k < nnzPerRow; k++ {
+			d.Set(i, rand.Intn(c), rnd())
+		}
+	}
+	return NewCSRFrom(d), d
+}
+
+func TestCSRMulVec(t *testing.T) {
+	for _, test := range []struct {
+		r, c, nnz int
+	}{
+		{r: 1, c: 1, nnz: 1},
+		{r: 5, c: 7, nnz: 2},
+		{r: 20, c: 10, nnz: 3},
+		{r: 10, c: 30, nnz: 0},
+	} {
+		a, d := randCSR(test.r, test.c, test.nnz, rand.NormFloat64)
+		for _, trans := range []bool{false, true} {
+			var am, dm Matrix = a, d
+			n := test.c
+			if trans {
+				am, dm = a.T(), d.T()
+				n = test.r
+			}
+			x := NewVector(n, nil)
+			for i := 0; i < n; i++ {
+				x.SetVec(i, rand.NormFloat64())
+			}
+			var got, want Vector
+			got.MulVec(am, x)
+			want.MulVec(dm, x)
+			if !EqualApprox(&got, &want, 1e-14) {
+				t.Errorf("unexpected product for %d×%d transpose=%t:\ngot: %v\nwant: %v",
+					test.r, test.c, trans, got.RawVector().Data, want.RawVector().Data)
+			}
+		}
+	}
+}
+
+func BenchmarkMulVecCSR2000(b *testing.B)   { csrMulVecBench(b, 2000, 5, false) }
+func BenchmarkMulVecDense2000(b *testing.B) { csrMulVecBench(b, 2000, 5, true) }
+func csrMulVecBench(b *testing.B, n, nnzPerRow int, dense bool) {
+	b.StopTimer()
+	a, d := randCSR(n, n, nnzPerRow, rand.NormFloat64)
+	var m Matrix = a
+	if dense {
+		m = d
+	}
+	x := NewVector(n, nil)
+	for i := 0; i < n; i++ {
+		x.SetVec(i, rand.NormFloat64())
+	}
+	var v Vector
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		v.MulVec(m, x)
+	}
+}
+
+func TestCSRAdd(t *testing.T) {
+	for _, test := range []struct {
+		a, b *Dense
+		nnz  int
+	}{
+		{
+			// Structurally overlapping patterns, including a cancellation.
+			a: NewDense(3, 3, []float64{
+				1, 0, 2,
+				0, 3, 0,
+				4, 0, 0,
+			}),
+			b: NewDense(3, 3, []float64{
+				5, 0, -2,
+				0, 6, 0,
+				7, 0, 0,
+			}),
+			nnz: 4,
+		},
+		{
+			// Disjoint patterns.
+			a: NewDense(2, 4, []float64{
+				1, 0, 2, 0,
+				0, 0, 0, 0,
+			}),
+			b: NewDense(2, 4, []float64{
+				0, 3, 0, 4,
+				5, 0, 0, 6,
+			}),
+			nnz: 6,
+		},
+		{
+			// Partially overlapping patterns.
+			a: NewDense(2, 3, []float64{
+				1, 2, 0,
+				0, 0, 3,
+			}),
+			b: NewDense(2, 3, []float64{
+				0, 4, 5,
+				6, 0, 7,
+			}),
+			nnz: 5,
+		},
+	} {
+		a, b := NewCSRFrom(test.a), NewCSRFrom(test.b)
+		var want Dense
+		want.Add(test.a, test.b)
+
+		var got CSR
+		if err := got.Add(a, b); err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		if !Equal(&got, &want) {
+			t.Errorf("unexpected sum:\ngot:\n%v\nwant:\n%v", Formatted(&got), Formatted(&want))
+		}
+		if got.NNZ() != test.nnz {
+			t.Errorf("unexpected number of stored elements: got: %d want: %d", got.NNZ(), test.nnz)
+		}
+		// The result must be a valid CSR structure.
+		NewCSR(got.rows, got.cols, got.indptr, got.ind, got.data)
+
+		// Adding into an operand must give the same result.
+		if err := a.Add(a, b); err != nil {
+			t.Errorf("unexpected error for in place addition: %v", err)
+		}
+		if !Equal(a, &want) {
+			t.Errorf("unexpected in place sum:\ngot:\n%v\nwant:\n%v", Formatted(a), Formatted(&want))
+		}
+	}
+
+	var got CSR
+	a := NewCSRFrom(NewDense(2, 3, nil))
+	if err := got.Add(a, NewCSRFrom(NewDense(3, 2, nil))); err != matrix.ErrShape {
+		t.Errorf("unexpected error for mismatched dimensions: got: %v want: %v", err, matrix.ErrShape)
+	}
+}
+
+func TestCSRTranspose(t *testing.T) {
+	d := NewDense(3, 4, []float64{
+		1, 0, 0, 2,
+		0, 0, 0, 0,
+		0, 3, 4, 0,
+	})
+	a := NewCSRFrom(d)
+	at, ok := a.T().(*CSC)
+	if !ok {
+		t.Fatalf("unexpected transpose type: got: %T want: *CSC", a.T())
+	}
+	if r, c := at.Dims(); r != 4 || c != 3 {
+		t.Errorf("unexpected transpose dimensions: got: %d×%d want: 4×3", r, c)
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 4; j++ {
+			if v, want := at.At(j, i), a.At(i, j); v != want {
+				t.Errorf("unexpected transpose element (%d, %d): got: %v want: %v", j, i, v, want)
+			}
+		}
+	}
+	if at.NNZ() != a.NNZ() {
+		t.Errorf("unexpected transpose stored elements: got: %d want: %d", at.NNZ(), a.NNZ())
+	}
+
+	// Transposing back must give the original matrix.
+	if back, ok := at.T().(*CSR); !ok || !Equal(back, a) {
+		t.Errorf("unexpected double transpose: got: %T", at.T())
+	}
+	if u, trans := untranspose(at); !trans {
+		t.Errorf("expected CSC to untranspose")
+	} else if _, ok := u.(*CSR); !ok {
+		t.Errorf("unexpected untransposed type: got: %T want: *CSR", u)
+	}
+
+	// The CSR visits its stored elements by row and the CSC
+	// of its transpose visits the same elements by column.
+	type elem struct {
+		i, j int
+		v    float64
+	}
+	wantCSR := []elem{{0, 0, 1}, {0, 3, 2}, {2, 1, 3}, {2, 2, 4}}
+	var got []elem
+	a.DoNonZero(func(i, j int, v float64) { got = append(got, elem{i, j, v}) })
+	if !reflect.DeepEqual(got, wantCSR) {
+		t.Errorf("unexpected CSR iteration: got: %v want: %v", got, wantCSR)
+	}
+	wantCSC := []elem{{0, 0, 1}, {3, 0, 2}, {1, 2, 3}, {2, 2, 4}}
+	got = got[:0]
+	at.DoNonZero(func(i, j int, v float64) { got = append(got, elem{i, j, v}) })
+	if !reflect.DeepEqual(got, wantCSC) {
+		t.Errorf("unexpected CSC iteration: got: %v want: %v", got, wantCSC)
+	}
+
+	// A CSC built directly must match the equivalent dense matrix.
+	c := NewCSC(3, 2, []int{0, 2, 3}, []int{0, 2, 1}, []float64{1, 2, 3})
+	if want := NewDense(3, 2, []float64{1, 0, 0, 3, 2, 0}); !Equal(c, want) {
+		t.Errorf("unexpected CSC:\ngot:\n%v\nwant:\n%v", Formatted(c), Formatted(want))
+	}
+	panicked, message := panics(func() { NewCSC(2, 2, []int{0, 1, 2}, []int{0, 2}, []float64{1, 2}) })
+	if !panicked || message != matrix.ErrRowAccess.Error() {
+		t.Errorf("unexpected panic for out of range row index: got: %q want: %q", message, matrix.ErrRowAccess)
+	}
+
+	// Products with the CSC must use the underlying CSR.
+	x := NewVector(3, []float64{1, 2, 3})
+	var got1, want1 Vector
+	got1.MulVec(at, x)
+	want1.MulVec(d.T(), x)
+	if !Equal(&got1, &want1) {
+		t.Errorf("unexpected CSC product: got: %v want: %v", got1.RawVector().Data, want1.RawVector().Data)
+	}
+}
+
+func TestILU0(t *testing.T) {
+	for _, test := range []struct {
+		a     *Dense
+		exact bool
+	}{
+		{
+			// A tridiagonal matrix has no fill-in, so the
+			// incomplete factors are the complete factors.
+			a: NewDense(4, 4, []float64{
+				4, -1, 0, 0,
+				-1, 4, -1, 0,
+				0, -1, 4, -1,
+				0, 0, -1, 4,
+			}),
+			exact: true,
+		},
+		{
+			// A five-point Laplacian on a 3×3 grid has fill-in
+			// outside its pattern that ILU0 discards.
+			a: laplacian2D(3),
+		},
+	} {
+		a := NewCSRFrom(test.a)
+		l, u, err := ILU0(a)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		n, _ := a.Dims()
+		var lu Dense
+		lu.Mul(l, u)
+		for i := 0; i < n; i++ {
+			if l.At(i, i) != 1 {
+				t.Errorf("unexpected diagonal of L at %d: got: %v want: 1", i, l.At(i, i))
+			}
+			for j := 0; j < n; j++ {
+				if j > i && l.At(i, j) != 0 {
+					t.Errorf("L not lower triangular at (%d, %d)", i, j)
+				}
+				if j < i && u.At(i, j) != 0 {
+					t.Errorf("U not upper triangular at (%d, %d)", i, j)
+				}
+				if test.a.At(i, j) == 0 && (l.At(i, j) != 0 || u.At(i, j) != 0) {
+					t.Errorf("factor fill-in outside the pattern of A at (%d, %d)", i, j)
+				}
+				if (test.exact || test.a.At(i, j) != 0) && math.Abs(lu.At(i, j)-test.a.At(i, j)) > 1e-14 {
+					t.Errorf("unexpected element of LU at (%d, %d): got: %v want: %v", i, j, lu.At(i, j), test.a.At(i, j))
+				}
+			}
+		}
+		if !test.exact && EqualApprox(&lu, test.a, 1e-14) {
+			t.Errorf("expected incomplete factors to differ from A outside its pattern")
+		}
+	}
+
+	for _, test := range []struct {
+		a   *CSR
+		err error
+	}{
+		{a: NewCSRFrom(NewDense(2, 3, []float64{1, 0, 0, 0, 1, 0})), err: matrix.ErrSquare},
+		{a: NewCSRFrom(NewDense(2, 2, []float64{0, 1, 1, 1})), err: matrix.ErrSingular},
+		{a: NewCSRFrom(NewDense(2, 2, []float64{1, 1, 1, 1})), err: matrix.ErrSingular},
+	} {
+		if _, _, err := ILU0(test.a); err != test.err {
+			t.Errorf("unexpected error: got: %v want: %v", err, test.err)
+		}
+	}
+}
+
+// laplacian2D returns the five-point finite difference Laplacian
+// on an n×n grid.
+func laplacian2D(n int) *Dense {
+	a := NewDense(n*n, n*n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			k := i*n + j
+			a.Set(k, k, 4)
+			if i > 0 {
+				a.Set(k, k-n, -1)
+			}
+			if i < n-1 {
+				a.Set(k, k+n, -1)
+			}
+			if j > 0 {
+				a.Set(k, k-1, -1)
+			}
+			if j < n-1 {
+				a.Set(k, k+1, -1)
+			}
+		}
+	}
+	return a
+}
@@ -237,6 +237,75 @@ func (m *Dense) rowView(r int) []float64 {
 	return m.mat.Data[r*m.mat.Stride : r*m.mat.Stride+m.mat.Cols]
 }
 
+// Diagonal copies the elements of the diagonal at the given offset into dst
+// and returns the result. An offset of 0 is the main diagonal, positive offsets
+// select diagonals above the main diagonal and negative offsets select diagonals
+// below it, so element i of the result is m.At(i, i+offset) for a non-negative
+// offset and m.At(i-offset, i) otherwise.
+//
+// The length of dst must equal the length of the diagonal, unless dst is nil
+// in which case a new slice is allocated. If the offset is outside the matrix,
+// Diagonal returns an empty slice.
+func (m *Dense) Diagonal(offset int, dst []float64) []float64 {
+	n := diagLen(m.mat.Rows, m.mat.Cols, offset)
+	if n == 0 {
+		return dst[:0]
+	}
+	if dst == nil {
+		dst = make([]float64, n)
+	}
+	if len(dst) != n {
+		panic(matrix.ErrSliceLengthMismatch)
+	}
+	blas64.Copy(n,
+		blas64.Vector{Inc: m.mat.Stride + 1, Data: m.mat.Data[diagOffset(m.mat.Stride, offset):]},
+		blas64.Vector{Inc: 1, Data: dst},
+	)
+	return dst
+}
+
+// SetDiagonal sets the elements of the diagonal at the given offset to the
+// values in data. The offset is interpreted as for Diagonal. SetDiagonal will
+// panic with matrix.ErrShape if len(data) does not equal the length of the
+// diagonal.
+func (m *Dense) SetDiagonal(offset int, data []float64) {
+	n := diagLen(m.mat.Rows, m.mat.Cols, offset)
+	if len(data) != n {
+		panic(matrix.ErrShape)
+	}
+	if n == 0 {
+		return
+	}
+	blas64.Copy(n,
+		blas64.Vector{Inc: 1, Data: data},
+		blas64.Vector{Inc: m.mat.Stride + 1, Data: m.mat.Data[diagOffset(m.mat.Stride, offset):]},
+	)
+}
+
+// diagLen returns the length of the diagonal at the given offset in an r×c
+// matrix, or zero if the offset is outside the matrix.
+func diagLen(r, c, offset int) int {
+	if offset >= 0 {
+		if offset >= c {
+			return 0
+		}
+		return min(r, c-offset)
+	}
+	if -offset >= r {
+		return 0
+	}
+	return min(r+offset, c)
+}
+
+// diagOffset returns the index into the data of a matrix with the given stride
+// of the first element of the diagonal at the given offset.
+func diagOffset(stride, offset int) int {
+	if offset >= 0 {
+		return offset
+	}
+	return -offset * stride
+}
+
 // View returns a new Matrix that shares backing data with the receiver.
 // The new matrix is located from row i, column j extending r rows and c
 // columns. View panics if the view is outside the bounds of the receiver.
@@ -441,6 +510,29 @@ func (m *Dense) Copy(a Matrix) (r, c int) {
 	return r, c
 }
 
+// CopyBlock copies the elements of src into the receiver with the top-left
+// element of src placed at position (i, j) of the receiver. The copied block
+// is clipped to the bounds of the receiver and CopyBlock returns the number
+// of elements copied. CopyBlock will panic if (i, j) is not a valid position
+// in the receiver.
+//
+// Copying between overlapping views of the same data gives undefined results
+// unless src is the receiver itself.
+func (m *Dense) CopyBlock(i, j int, src Matrix) int {
+	r, c := m.Dims()
+	if i < 0 || i >= r {
+		panic(matrix.ErrRowAccess)
+	}
+	if j < 0 || j >= c {
+		panic(matrix.ErrColAccess)
+	}
+	if src == m {
+		src = DenseCopyOf(src)
+	}
+	br, bc := m.View(i, j, r-i, c-j).(*Dense).Copy(src)
+	return br * bc
+}
+
 // Stack appends the rows of b onto the rows of a, placing the result into the
 // receiver with b placed in the greater indexed rows. Stack will panic if the
 // two input matrices do not have the same number of columns or the constructed
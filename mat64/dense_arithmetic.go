@@ -6,9 +6,11 @@ package mat64
 
 import (
 	"math"
+	"sort"
 
 	"github.com/gonum/blas"
 	"github.com/gonum/blas/blas64"
+	"github.com/gonum/floats"
 	"github.com/gonum/lapack/lapack64"
 	"github.com/gonum/matrix"
 )
@@ -80,6 +82,71 @@ func (m *Dense) Add(a, b Matrix) {
 	}
 }
 
+// AddBroadcast adds a and b, placing the result in the receiver. The
+// dimensions of a and b are broadcast in the manner of NumPy: each dimension
+// must either match between a and b or be one in one of them, in which case
+// that operand is repeated along the dimension. This allows a row vector
+// (1×c), column vector (r×1) or scalar (1×1) operand to be added to an r×c
+// matrix.
+//
+// AddBroadcast returns matrix.ErrShape if the dimensions of a and b are not
+// compatible, leaving the receiver unmodified. AddBroadcast will panic if the
+// receiver partially overlaps a or b.
+func (m *Dense) AddBroadcast(a, b Matrix) error {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	r, okr := broadcastDim(ar, br)
+	c, okc := broadcastDim(ac, bc)
+	if !okr || !okc {
+		return matrix.ErrShape
+	}
+	if ar == br && ac == bc {
+		m.Add(a, b)
+		return nil
+	}
+
+	m.reuseAs(r, c)
+
+	// An operand that is the receiver, possibly transposed, is read
+	// from its original storage while the result is built in a
+	// workspace. Partial overlap with the receiver is not allowed.
+	var alias bool
+	for _, v := range []Matrix{a, b} {
+		vU, _ := untranspose(v)
+		if vU == m {
+			alias = true
+			continue
+		}
+		if rm, ok := vU.(RawMatrixer); ok {
+			m.checkOverlap(rm.RawMatrix())
+		}
+	}
+	if alias {
+		var restore func()
+		m, restore = m.isolatedWorkspace(m)
+		defer restore()
+	}
+
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			m.set(i, j, a.At(i%ar, j%ac)+b.At(i%br, j%bc))
+		}
+	}
+	return nil
+}
+
+// broadcastDim returns the broadcast length of two dimensions of length a
+// and b, and whether the dimensions are compatible.
+func broadcastDim(a, b int) (n int, ok bool) {
+	switch {
+	case a == b, b == 1:
+		return a, true
+	case a == 1:
+		return b, true
+	}
+	return 0, false
+}
+
 // Sub subtracts the matrix b from a, placing the result in the receiver. Sub
 // will panic if the two matrices do not have the same shape.
 func (m *Dense) Sub(a, b Matrix) {
@@ -145,6 +212,10 @@ func (m *Dense) Sub(a, b Matrix) {
 // MulElem performs element-wise multiplication of a and b, placing the result
 // in the receiver. MulElem will panic if the two matrices do not have the same
 // shape.
+//
+// As for Add, a zero receiver is resized to the shape of a and b, while a
+// non-zero receiver of a different shape causes a panic with matrix.ErrShape.
+// MulElem will panic if the receiver partially overlaps a or b.
 func (m *Dense) MulElem(a, b Matrix) {
 	ar, ac := a.Dims()
 	br, bc := b.Dims()
@@ -208,6 +279,14 @@ func (m *Dense) MulElem(a, b Matrix) {
 // DivElem performs element-wise division of a by b, placing the result
 // in the receiver. DivElem will panic if the two matrices do not have the same
 // shape.
+//
+// Division by a zero element of b does not panic; the corresponding element
+// of the result is ±Inf, or NaN if the element of a is also zero, following
+// IEEE 754 floating point division.
+//
+// As for Add, a zero receiver is resized to the shape of a and b, while a
+// non-zero receiver of a different shape causes a panic with matrix.ErrShape.
+// DivElem will panic if the receiver partially overlaps a or b.
 func (m *Dense) DivElem(a, b Matrix) {
 	ar, ac := a.Dims()
 	br, bc := b.Dims()
@@ -351,6 +430,35 @@ func (m *Dense) Mul(a, b Matrix) {
 		bT = blas.Trans
 	}
 
+	// Multiplication by a diagonal matrix scales the rows or columns
+	// of the other operand. Diagonal matrices are their own transpose.
+	if aU, ok := aU.(*DiagDense); ok {
+		if bUrm, ok := bU.(RawMatrixer); ok && restore == nil {
+			m.checkOverlap(bUrm.RawMatrix())
+		}
+		m.Copy(b)
+		for i, v := range aU.data {
+			row := m.rowView(i)
+			for j := range row {
+				row[j] *= v
+			}
+		}
+		return
+	}
+	if bU, ok := bU.(*DiagDense); ok {
+		if aUrm, ok := aU.(RawMatrixer); ok && restore == nil {
+			m.checkOverlap(aUrm.RawMatrix())
+		}
+		m.Copy(a)
+		for i := 0; i < ar; i++ {
+			row := m.rowView(i)
+			for j, v := range bU.data {
+				row[j] *= v
+			}
+		}
+		return
+	}
+
 	// Some of the cases do not have a transpose option, so create
 	// temporary memory.
 	// C = A^T * B = (B^T * A)^T
@@ -569,6 +677,67 @@ func strictCopy(m *Dense, a Matrix) {
 	}
 }
 
+// MulTo computes alpha * a * b + beta * m, placing the result in the receiver.
+// If beta is zero, the initial contents of the receiver are ignored and MulTo
+// behaves as Mul followed by scaling by alpha. Otherwise the receiver must be
+// ar×bc where ar is the number of rows in a and bc is the number of columns in
+// b. MulTo will panic with matrix.ErrShape if the number of columns in a does
+// not equal the number of rows in b or if beta is non-zero and the receiver
+// has the wrong shape.
+func (m *Dense) MulTo(alpha float64, a, b Matrix, beta float64) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ac != br {
+		panic(matrix.ErrShape)
+	}
+
+	if beta == 0 {
+		m.Mul(a, b)
+		if alpha != 1 {
+			m.Scale(alpha, m)
+		}
+		return
+	}
+	if m.mat.Rows != ar || m.mat.Cols != bc {
+		panic(matrix.ErrShape)
+	}
+
+	aU, aTrans := untranspose(a)
+	bU, bTrans := untranspose(b)
+	if m != aU && m != bU {
+		if aUrm, ok := aU.(RawMatrixer); ok {
+			if bUrm, ok := bU.(RawMatrixer); ok {
+				amat := aUrm.RawMatrix()
+				bmat := bUrm.RawMatrix()
+				m.checkOverlap(amat)
+				m.checkOverlap(bmat)
+				aT := blas.NoTrans
+				if aTrans {
+					aT = blas.Trans
+				}
+				bT := blas.NoTrans
+				if bTrans {
+					bT = blas.Trans
+				}
+				blas64.Gemm(aT, bT, alpha, amat, bmat, beta, m.mat)
+				return
+			}
+		}
+	}
+
+	// Compute the product with Mul to make use of its handling
+	// of structured operands, then accumulate.
+	w := getWorkspace(ar, bc, false)
+	w.Mul(a, b)
+	for i := 0; i < ar; i++ {
+		row := m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+bc]
+		for j, v := range w.mat.Data[i*w.mat.Stride : i*w.mat.Stride+bc] {
+			row[j] = alpha*v + beta*row[j]
+		}
+	}
+	putWorkspace(w)
+}
+
 // Exp calculates the exponential of the matrix a, e^a, placing the result
 // in the receiver. Exp will panic with matrix.ErrShape if a is not square.
 //
@@ -649,15 +818,39 @@ func (m *Dense) Exp(a Matrix) {
 }
 
 // Pow calculates the integral power of the matrix a to n, placing the result
-// in the receiver. Pow will panic if n is negative or if a is not square.
+// in the receiver. If n is negative, the inverse of a is computed using its
+// LU factorization and raised to the power -n, so the result is (a^-1)^-n.
+// Pow will panic if a is not square, and with matrix.ErrSingular if n is
+// negative and a is singular. No error is reported if a is near-singular;
+// use Inverse and Cond to check the conditioning of a.
 func (m *Dense) Pow(a Matrix, n int) {
-	if n < 0 {
-		panic("matrix: illegal power")
-	}
 	r, c := a.Dims()
 	if r != c {
 		panic(matrix.ErrShape)
 	}
+	if n < 0 {
+		var lu LU
+		lu.Factorize(a)
+		inv := getWorkspace(r, c, true)
+		for i := 0; i < r; i++ {
+			inv.set(i, i, 1)
+		}
+		err := inv.SolveLU(&lu, false, inv)
+		if cond, ok := err.(matrix.Condition); ok && math.IsInf(float64(cond), 1) {
+			putWorkspace(inv)
+			panic(matrix.ErrSingular)
+		}
+		if -n < 0 {
+			// n is the most negative int, so -n overflows. Take
+			// one factor of the inverse out of the power.
+			m.Pow(inv, -(n + 1))
+			m.Mul(m, inv)
+		} else {
+			m.Pow(inv, -n)
+		}
+		putWorkspace(inv)
+		return
+	}
 
 	m.reuseAs(r, c)
 
@@ -799,6 +992,167 @@ func (m *Dense) Apply(fn func(i, j int, v float64) float64, a Matrix) {
 	}
 }
 
+// NormalizeRows scales each row of a to have unit p-norm, placing the result
+// in the receiver. Rows of a with a p-norm of zero are left as zeros.
+// NormalizeRows will panic with matrix.ErrNormOrder if p is not positive.
+func (m *Dense) NormalizeRows(a Matrix, p float64) {
+	if !(p > 0) {
+		panic(matrix.ErrNormOrder)
+	}
+	r, c := a.Dims()
+	norms := make([]float64, r)
+	row := make([]float64, c)
+	for i := range norms {
+		norms[i] = floats.Norm(Row(row, i, a), p)
+	}
+	m.Apply(func(i, _ int, v float64) float64 {
+		if norms[i] == 0 {
+			return 0
+		}
+		return v / norms[i]
+	}, a)
+}
+
+// NormalizeCols scales each column of a to have unit p-norm, placing the result
+// in the receiver. Columns of a with a p-norm of zero are left as zeros.
+// NormalizeCols will panic with matrix.ErrNormOrder if p is not positive.
+func (m *Dense) NormalizeCols(a Matrix, p float64) {
+	if !(p > 0) {
+		panic(matrix.ErrNormOrder)
+	}
+	r, c := a.Dims()
+	norms := make([]float64, c)
+	col := make([]float64, r)
+	for j := range norms {
+		norms[j] = floats.Norm(Col(col, j, a), p)
+	}
+	m.Apply(func(_, j int, v float64) float64 {
+		if norms[j] == 0 {
+			return 0
+		}
+		return v / norms[j]
+	}, a)
+}
+
+// ImputeColMean places a copy of a into the receiver with the elements that
+// are marked as missing by a zero in the corresponding element of mask
+// replaced by the mean of the present elements in the same column of a.
+// Missing elements of a are not read, so they may hold any value including
+// NaN. Columns with no present elements are filled with zero.
+// ImputeColMean will panic with matrix.ErrShape if a and mask do not have the
+// same dimensions.
+func (m *Dense) ImputeColMean(a, mask Matrix) {
+	r, c := a.Dims()
+	if mr, mc := mask.Dims(); mr != r || mc != c {
+		panic(matrix.ErrShape)
+	}
+	means := make([]float64, c)
+	for j := range means {
+		var n int
+		for i := 0; i < r; i++ {
+			if mask.At(i, j) != 0 {
+				means[j] += a.At(i, j)
+				n++
+			}
+		}
+		if n != 0 {
+			means[j] /= float64(n)
+		}
+	}
+	m.Apply(func(i, j int, v float64) float64 {
+		if mask.At(i, j) == 0 {
+			return means[j]
+		}
+		return v
+	}, a)
+}
+
+// Rescale linearly maps the elements of a from the range [Min(a), Max(a)] to
+// the range [newMin, newMax], placing the result in the receiver. The minimum
+// and maximum elements of a map exactly to newMin and newMax. If all elements
+// of a are equal, every element of the result is newMin.
+func (m *Dense) Rescale(a Matrix, newMin, newMax float64) {
+	lo, hi := Min(a), Max(a)
+	m.Apply(func(_, _ int, v float64) float64 {
+		return rescale(v, lo, hi, newMin, newMax)
+	}, a)
+}
+
+// MinMaxScaleCols linearly maps each column of a independently from the range
+// of its elements to the range [newMin, newMax], placing the result in the
+// receiver. The minimum and maximum elements of each column map exactly to
+// newMin and newMax. Columns whose elements are all equal map to newMin.
+func (m *Dense) MinMaxScaleCols(a Matrix, newMin, newMax float64) {
+	r, c := a.Dims()
+	lo := make([]float64, c)
+	hi := make([]float64, c)
+	col := make([]float64, r)
+	for j := 0; j < c; j++ {
+		Col(col, j, a)
+		lo[j], hi[j] = floats.Min(col), floats.Max(col)
+	}
+	m.Apply(func(_, j int, v float64) float64 {
+		return rescale(v, lo[j], hi[j], newMin, newMax)
+	}, a)
+}
+
+// QuantileNormalize places the quantile normalization of the columns of a
+// into the receiver, so that every column of the result has the same
+// distribution of values. The reference distribution is the mean across
+// columns of the sorted columns of a, and each element of a is replaced by
+// the reference value at its rank within its column. Tied elements of a
+// column are each replaced by the mean of the reference values at the ranks
+// they span.
+func (m *Dense) QuantileNormalize(a Matrix) {
+	r, c := a.Dims()
+	vals := make([][]float64, c)
+	inds := make([][]int, c)
+	ref := make([]float64, r)
+	for j := range vals {
+		vals[j] = Col(nil, j, a)
+		inds[j] = make([]int, r)
+		for i := range inds[j] {
+			inds[j][i] = i
+		}
+		sort.Stable(argsorter{vals: vals[j], inds: inds[j]})
+		for k, i := range inds[j] {
+			ref[k] += vals[j][i]
+		}
+	}
+	for k := range ref {
+		ref[k] /= float64(c)
+	}
+
+	// All elements of a have been read, so the receiver may share
+	// storage with a.
+	m.reuseAs(r, c)
+	for j, col := range vals {
+		ind := inds[j]
+		for lo := 0; lo < r; {
+			hi := lo + 1
+			for hi < r && col[ind[hi]] == col[ind[lo]] {
+				hi++
+			}
+			v := floats.Sum(ref[lo:hi]) / float64(hi-lo)
+			for _, i := range ind[lo:hi] {
+				m.set(i, j, v)
+			}
+			lo = hi
+		}
+	}
+}
+
+// rescale linearly maps v from [lo, hi] to [newMin, newMax], mapping all
+// values to newMin if lo and hi are equal. The interpolation is written so
+// that lo and hi map exactly to newMin and newMax.
+func rescale(v, lo, hi, newMin, newMax float64) float64 {
+	if lo == hi {
+		return newMin
+	}
+	t := (v - lo) / (hi - lo)
+	return newMin*(1-t) + newMax*t
+}
+
 // RankOne performs a rank-one update to the matrix a and stores the result
 // in the receiver. If a is zero, see Outer.
 //  m = a + alpha * x * y'
@@ -861,3 +1215,206 @@ func (m *Dense) Outer(alpha float64, x, y *Vector) {
 
 	blas64.Ger(alpha, x.mat, y.mat, m.mat)
 }
+
+// TensorProduct calculates the tensor (outer) product of a and b, flattened
+// into a matrix, and stores the result in the receiver. If a is ar×ac and b
+// is br×bc, the receiver is (ar*ac)×(br*bc) and holds the four-index tensor
+//  T[i][j][k][l] = a[i][j] * b[k][l]
+// with the index pair {i, j} flattened into the row index and {k, l} flattened
+// into the column index, both in row-major order. That is, the product a[i][j] * b[k][l]
+// is placed at
+//  row = i*ac + j
+//  col = k*bc + l
+// as returned by TensorIndex. The receiver is therefore the outer product of
+// the row-major vectorizations of a and b. This differs from the Kronecker
+// product, in which the row index interleaves i and k, and the column index
+// interleaves j and l.
+//
+// If the receiver is not zero it must be (ar*ac)×(br*bc), otherwise
+// TensorProduct will panic with matrix.ErrShape. TensorProduct will panic if
+// the receiver shares storage with a or b.
+func (m *Dense) TensorProduct(a, b Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	m.reuseAs(ar*ac, br*bc)
+	for _, v := range []Matrix{a, b} {
+		vU, _ := untranspose(v)
+		if rm, ok := vU.(RawMatrixer); ok {
+			m.checkOverlap(rm.RawMatrix())
+		}
+	}
+
+	bvec := make([]float64, br*bc)
+	for k := 0; k < br; k++ {
+		Row(bvec[k*bc:(k+1)*bc], k, b)
+	}
+	for i := 0; i < ar; i++ {
+		for j := 0; j < ac; j++ {
+			v := a.At(i, j)
+			row := m.rowView(i*ac + j)
+			for n, w := range bvec {
+				row[n] = v * w
+			}
+		}
+	}
+}
+
+// Kronecker calculates the Kronecker product of a and b, placing the result in
+// the receiver. If a is ar×ac and b is br×bc, the result is the
+// ar*br×ac*bc block matrix
+//  a[0][0]*b    a[0][1]*b    …  a[0][ac-1]*b
+//  a[1][0]*b    a[1][1]*b    …  a[1][ac-1]*b
+//  …
+//  a[ar-1][0]*b a[ar-1][1]*b …  a[ar-1][ac-1]*b
+// If the receiver is not zero it must be ar*br×ac*bc, otherwise Kronecker
+// will panic with matrix.ErrShape. Kronecker will panic if the receiver
+// shares storage with a or b.
+func (m *Dense) Kronecker(a, b Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	m.reuseAs(ar*br, ac*bc)
+	for _, v := range []Matrix{a, b} {
+		vU, _ := untranspose(v)
+		if rm, ok := vU.(RawMatrixer); ok {
+			m.checkOverlap(rm.RawMatrix())
+		}
+	}
+
+	bw := getWorkspace(br, bc, false)
+	bw.Copy(b)
+	for i := 0; i < ar; i++ {
+		for j := 0; j < ac; j++ {
+			v := a.At(i, j)
+			for k := 0; k < br; k++ {
+				off := (i*br+k)*m.mat.Stride + j*bc
+				dst := m.mat.Data[off : off+bc]
+				for l, w := range bw.rowView(k) {
+					dst[l] = v * w
+				}
+			}
+		}
+	}
+	putWorkspace(bw)
+}
+
+// KroneckerSum calculates the Kronecker sum of the square matrices a and b,
+//  a ⊕ b = a ⊗ I_p + I_n ⊗ b
+// where a is n×n, b is p×p and ⊗ denotes the Kronecker product, and stores
+// the np×np result in the receiver. The element of the result at row i*p+k
+// and column j*p+l is
+//  a[i][j]*δ(k,l) + δ(i,j)*b[k][l].
+// The Kronecker sum arises in discretizations of separable operators on
+// two-dimensional grids. The direct sum of matrices is given by BlockDiag.
+//
+// KroneckerSum will panic with matrix.ErrSquare if a or b is not square. If
+// the receiver is not zero it must be np×np, otherwise KroneckerSum will panic
+// with matrix.ErrShape. KroneckerSum will panic if the receiver shares storage
+// with a or b.
+func (m *Dense) KroneckerSum(a, b Matrix) {
+	n, ac := a.Dims()
+	p, bc := b.Dims()
+	if n != ac || p != bc {
+		panic(matrix.ErrSquare)
+	}
+	m.reuseAs(n*p, n*p)
+	for _, v := range []Matrix{a, b} {
+		vU, _ := untranspose(v)
+		if rm, ok := vU.(RawMatrixer); ok {
+			m.checkOverlap(rm.RawMatrix())
+		}
+	}
+	for i := 0; i < n*p; i++ {
+		zero(m.rowView(i))
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			v := a.At(i, j)
+			if v == 0 {
+				continue
+			}
+			for k := 0; k < p; k++ {
+				m.mat.Data[(i*p+k)*m.mat.Stride+j*p+k] += v
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		for k := 0; k < p; k++ {
+			row := m.mat.Data[(i*p+k)*m.mat.Stride+i*p : (i*p+k)*m.mat.Stride+i*p+p]
+			for l := range row {
+				row[l] += b.At(k, l)
+			}
+		}
+	}
+}
+
+// TensorIndex returns the row and column of the receiver of TensorProduct(a, b)
+// that holds the product a[i][j] * b[k][l], where a is ar×ac and b is br×bc.
+// The inverse mapping is given by
+//  i, j = row/ac, row%ac
+//  k, l = col/bc, col%bc
+// TensorIndex will panic with matrix.ErrIndexOutOfRange if any of the indices
+// is outside its matrix.
+func TensorIndex(ar, ac, br, bc, i, j, k, l int) (row, col int) {
+	if i < 0 || i >= ar || j < 0 || j >= ac || k < 0 || k >= br || l < 0 || l >= bc {
+		panic(matrix.ErrIndexOutOfRange)
+	}
+	return i*ac + j, k*bc + l
+}
+
+// Hessianish computes a central finite-difference approximation of the
+// Jacobian of grad at x, placing the result in the receiver. Element {i, j}
+// of the receiver is
+//  (grad(x + eps*e_j)[i] - grad(x - eps*e_j)[i]) / (2*eps)
+// where e_j is the jth unit vector. When grad is the gradient of a scalar
+// function, the result approximates its Hessian, which may then be used,
+// for example, to compute a Newton step with Solve.
+//
+// The receiver must be zero-sized or have size len(grad(x))×len(x). The
+// elements of x are restored before Hessianish returns. Hessianish will panic
+// if eps is not positive.
+func (m *Dense) Hessianish(grad func(x *Vector) *Vector, x *Vector, eps float64) {
+	if !(eps > 0) {
+		panic(badFiniteStep)
+	}
+	var fwd []float64
+	for j := 0; j < x.Len(); j++ {
+		fwd = m.hessianishCol(grad, x, j, eps, fwd)
+	}
+}
+
+// badFiniteStep is the panic string used by Hessianish for a step that is
+// not positive.
+const badFiniteStep = "mat64: non-positive finite difference step"
+
+// hessianishCol computes column j of the finite-difference Jacobian for
+// Hessianish, using fwd as scratch for the forward difference. fwd is nil
+// for the first column, in which case the receiver is sized. The element
+// x[j] is restored before hessianishCol returns, even if grad or the
+// sizing of the receiver panics.
+func (m *Dense) hessianishCol(grad func(x *Vector) *Vector, x *Vector, j int, eps float64, fwd []float64) []float64 {
+	xj := x.at(j)
+	defer x.setVec(j, xj)
+
+	x.setVec(j, xj+eps)
+	g := grad(x)
+	if fwd == nil {
+		m.reuseAs(g.Len(), x.Len())
+		fwd = make([]float64, g.Len())
+	}
+	if g.Len() != len(fwd) {
+		panic(matrix.ErrShape)
+	}
+	for i := range fwd {
+		fwd[i] = g.at(i)
+	}
+
+	x.setVec(j, xj-eps)
+	g = grad(x)
+	if g.Len() != len(fwd) {
+		panic(matrix.ErrShape)
+	}
+	for i, f := range fwd {
+		m.mat.Data[i*m.mat.Stride+j] = (f - g.at(i)) / (2 * eps)
+	}
+	return fwd
+}
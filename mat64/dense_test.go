@@ -8,6 +8,7 @@ import (
 	"math"
 	"math/rand"
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/gonum/blas/blas64"
@@ -291,6 +292,90 @@ func TestSetRowColumn(t *testing.T) {
 	}
 }
 
+func TestDiagonal(t *testing.T) {
+	a := NewDense(3, 4, []float64{
+		1, 2, 3, 4,
+		5, 6, 7, 8,
+		9, 10, 11, 12,
+	})
+	for _, test := range []struct {
+		m      *Dense
+		offset int
+		want   []float64
+	}{
+		{m: a, offset: 0, want: []float64{1, 6, 11}},
+		{m: a, offset: 1, want: []float64{2, 7, 12}},
+		{m: a, offset: 2, want: []float64{3, 8}},
+		{m: a, offset: 3, want: []float64{4}},
+		{m: a, offset: 4, want: []float64{}},
+		{m: a, offset: -1, want: []float64{5, 10}},
+		{m: a, offset: -2, want: []float64{9}},
+		{m: a, offset: -3, want: []float64{}},
+		{m: a.View(1, 1, 2, 3).(*Dense), offset: 1, want: []float64{7, 12}},
+		{m: a.View(1, 1, 2, 3).(*Dense), offset: -1, want: []float64{10}},
+	} {
+		got := test.m.Diagonal(test.offset, nil)
+		if !floats.Equal(got, test.want) {
+			t.Errorf("unexpected diagonal for offset %d: got: %v want: %v", test.offset, got, test.want)
+		}
+		if len(test.want) == 0 {
+			continue
+		}
+		dst := make([]float64, len(test.want))
+		got = test.m.Diagonal(test.offset, dst)
+		if !floats.Equal(dst, test.want) || &got[0] != &dst[0] {
+			t.Errorf("unexpected diagonal in dst for offset %d: got: %v want: %v", test.offset, dst, test.want)
+		}
+	}
+
+	panicked, message := panics(func() { a.Diagonal(1, make([]float64, 2)) })
+	if !panicked || message != matrix.ErrSliceLengthMismatch.Error() {
+		t.Errorf("expected panic for short dst")
+	}
+}
+
+func TestSetDiagonal(t *testing.T) {
+	m := NewDense(3, 4, nil)
+	m.SetDiagonal(0, []float64{1, 2, 3})
+	m.SetDiagonal(1, []float64{4, 5, 6})
+	m.SetDiagonal(-2, []float64{7})
+	want := NewDense(3, 4, []float64{
+		1, 4, 0, 0,
+		0, 2, 5, 0,
+		7, 0, 3, 6,
+	})
+	if !Equal(m, want) {
+		t.Errorf("unexpected result for SetDiagonal: got: %v want: %v", m.mat.Data, want.mat.Data)
+	}
+	for _, offset := range []int{-2, -1, 0, 1, 2, 3} {
+		if !floats.Equal(m.Diagonal(offset, nil), want.Diagonal(offset, nil)) {
+			t.Errorf("unexpected round trip for offset %d", offset)
+		}
+	}
+
+	// Setting a diagonal of a view must only alter elements within the view.
+	v := NewDense(3, 3, nil)
+	v.View(1, 1, 2, 2).(*Dense).SetDiagonal(0, []float64{1, 1})
+	if !Equal(v, NewDense(3, 3, []float64{0, 0, 0, 0, 1, 0, 0, 0, 1})) {
+		t.Errorf("unexpected result for SetDiagonal on view: got: %v", v.mat.Data)
+	}
+
+	m.SetDiagonal(4, nil)
+	for _, test := range []struct {
+		offset int
+		data   []float64
+	}{
+		{offset: 0, data: []float64{1, 2}},
+		{offset: 1, data: []float64{1, 2, 3, 4}},
+		{offset: -3, data: []float64{1}},
+	} {
+		panicked, message := panics(func() { m.SetDiagonal(test.offset, test.data) })
+		if !panicked || message != matrix.ErrShape.Error() {
+			t.Errorf("expected shape panic for offset %d with length %d", test.offset, len(test.data))
+		}
+	}
+}
+
 func TestRowColView(t *testing.T) {
 	for _, test := range []struct {
 		mat [][]float64
@@ -536,6 +621,102 @@ func TestAdd(t *testing.T) {
 	testTwoInput(t, "Add", &Dense{}, method, denseComparison, legalTypesAll, legalSizeSameRectangular, 1e-14)
 }
 
+func TestAddBroadcast(t *testing.T) {
+	a := NewDense(2, 3, []float64{
+		1, 2, 3,
+		4, 5, 6,
+	})
+	for i, test := range []struct {
+		a, b Matrix
+		want *Dense
+	}{
+		{
+			a: a, b: NewDense(1, 3, []float64{10, 20, 30}),
+			want: NewDense(2, 3, []float64{
+				11, 22, 33,
+				14, 25, 36,
+			}),
+		},
+		{
+			a: NewDense(2, 1, []float64{10, 20}), b: a,
+			want: NewDense(2, 3, []float64{
+				11, 12, 13,
+				24, 25, 26,
+			}),
+		},
+		{
+			a: a, b: NewDense(1, 1, []float64{-1}),
+			want: NewDense(2, 3, []float64{
+				0, 1, 2,
+				3, 4, 5,
+			}),
+		},
+		{
+			a: NewDense(2, 1, []float64{1, 2}), b: NewDense(1, 3, []float64{10, 20, 30}),
+			want: NewDense(2, 3, []float64{
+				11, 21, 31,
+				12, 22, 32,
+			}),
+		},
+		{
+			a: a, b: a,
+			want: NewDense(2, 3, []float64{
+				2, 4, 6,
+				8, 10, 12,
+			}),
+		},
+	} {
+		var got Dense
+		if err := got.AddBroadcast(test.a, test.b); err != nil {
+			t.Errorf("unexpected error for test %d: %v", i, err)
+			continue
+		}
+		if !Equal(&got, test.want) {
+			t.Errorf("unexpected result for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(&got), Formatted(test.want))
+		}
+	}
+
+	// Broadcasting into an operand must give the same result.
+	m := DenseCopyOf(a)
+	if err := m.AddBroadcast(m, NewDense(1, 3, []float64{10, 20, 30})); err != nil {
+		t.Errorf("unexpected error for in place broadcast: %v", err)
+	}
+	if want := NewDense(2, 3, []float64{11, 22, 33, 14, 25, 36}); !Equal(m, want) {
+		t.Errorf("unexpected in place result:\ngot:\n%v\nwant:\n%v", Formatted(m), Formatted(want))
+	}
+
+	// Broadcasting from the transpose of the receiver must read
+	// the original elements.
+	sq := NewDense(2, 2, []float64{
+		1, 2,
+		3, 4,
+	})
+	if err := sq.AddBroadcast(sq.T(), NewDense(1, 1, nil)); err != nil {
+		t.Errorf("unexpected error for transposed in place broadcast: %v", err)
+	}
+	if want := NewDense(2, 2, []float64{1, 3, 2, 4}); !Equal(sq, want) {
+		t.Errorf("unexpected transposed in place result:\ngot:\n%v\nwant:\n%v", Formatted(sq), Formatted(want))
+	}
+	panicked, message := panics(func() {
+		m := NewDense(10, 10, nil)
+		m.View(1, 1, 2, 2).(*Dense).AddBroadcast(m.View(2, 2, 2, 2), NewDense(1, 1, nil))
+	})
+	if !panicked || message != regionOverlap {
+		t.Errorf("unexpected panic for overlapping operand: got: %q want: %q", message, regionOverlap)
+	}
+
+	for _, b := range []Matrix{
+		NewDense(1, 2, nil),
+		NewDense(3, 1, nil),
+		NewDense(3, 3, nil),
+	} {
+		var got Dense
+		if err := got.AddBroadcast(a, b); err != matrix.ErrShape {
+			t.Errorf("unexpected error for incompatible shapes: got: %v want: %v", err, matrix.ErrShape)
+		}
+	}
+}
+
 func TestSub(t *testing.T) {
 	for i, test := range []struct {
 		a, b, r [][]float64
@@ -697,6 +878,36 @@ func TestMulElem(t *testing.T) {
 		t.Errorf("unexpected panic message: got: %q want: %q", message, regionOverlap)
 	}
 
+	// Overlapping storage with a different stride can not be
+	// checked precisely, so it must always panic.
+	for _, second := range []bool{false, true} {
+		panicked, message = panics(func() {
+			m := NewDense(10, 10, nil)
+			a := NewDense(5, 5, nil)
+			b := &Dense{
+				mat: blas64.General{
+					Rows:   5,
+					Cols:   5,
+					Stride: 6,
+					Data:   m.mat.Data[1:30],
+				},
+				capRows: 5,
+				capCols: 5,
+			}
+			if second {
+				m.View(0, 0, 5, 5).(*Dense).MulElem(a, b)
+			} else {
+				m.View(0, 0, 5, 5).(*Dense).MulElem(b, a)
+			}
+		})
+		if !panicked {
+			t.Errorf("expected panic for overlapping matrices with different strides (second operand: %t)", second)
+		}
+		if message != mismatchedStrides {
+			t.Errorf("unexpected panic message: got: %q want: %q", message, mismatchedStrides)
+		}
+	}
+
 	method := func(receiver, a, b Matrix) {
 		type ElemMuler interface {
 			MulElem(a, b Matrix)
@@ -755,6 +966,11 @@ func TestDivElem(t *testing.T) {
 			[][]float64{{1, 2, 3}, {4, 5, 6}},
 			[][]float64{{1, 1, 1}, {1, 1, 1}},
 		},
+		{
+			[][]float64{{-2, 3, 0}, {0, -4, 5}},
+			[][]float64{{0, 0, 0}, {2, math.Copysign(0, -1), math.Copysign(0, -1)}},
+			[][]float64{{math.Inf(-1), math.Inf(1), math.NaN()}, {0, math.Inf(1), math.Inf(-1)}},
+		},
 	} {
 		a := NewDense(flatten(test.a))
 		b := NewDense(flatten(test.b))
@@ -800,6 +1016,36 @@ func TestDivElem(t *testing.T) {
 		t.Errorf("unexpected panic message: got: %q want: %q", message, regionOverlap)
 	}
 
+	// Overlapping storage with a different stride can not be
+	// checked precisely, so it must always panic.
+	for _, second := range []bool{false, true} {
+		panicked, message = panics(func() {
+			m := NewDense(10, 10, nil)
+			a := NewDense(5, 5, nil)
+			b := &Dense{
+				mat: blas64.General{
+					Rows:   5,
+					Cols:   5,
+					Stride: 6,
+					Data:   m.mat.Data[1:30],
+				},
+				capRows: 5,
+				capCols: 5,
+			}
+			if second {
+				m.View(0, 0, 5, 5).(*Dense).DivElem(a, b)
+			} else {
+				m.View(0, 0, 5, 5).(*Dense).DivElem(b, a)
+			}
+		})
+		if !panicked {
+			t.Errorf("expected panic for overlapping matrices with different strides (second operand: %t)", second)
+		}
+		if message != mismatchedStrides {
+			t.Errorf("unexpected panic message: got: %q want: %q", message, mismatchedStrides)
+		}
+	}
+
 	method := func(receiver, a, b Matrix) {
 		type ElemDiver interface {
 			DivElem(a, b Matrix)
@@ -923,6 +1169,92 @@ func randDense(size int, rho float64, rnd func() float64) (*Dense, error) {
 	return d, nil
 }
 
+func TestMulTo(t *testing.T) {
+	randMat := func(r, c int) *Dense {
+		m := NewDense(r, c, nil)
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				m.Set(i, j, rand.NormFloat64())
+			}
+		}
+		return m
+	}
+	sym := NewSymDense(3, []float64{
+		1, 2, 3,
+		0, 4, 5,
+		0, 0, 6,
+	})
+	tri := NewTriDense(3, true, []float64{
+		1, 2, 3,
+		0, 4, 5,
+		0, 0, 6,
+	})
+	for i, test := range []struct {
+		a, b        Matrix
+		alpha, beta float64
+	}{
+		{a: randMat(3, 4), b: randMat(4, 2), alpha: 1, beta: 1},
+		{a: randMat(3, 4), b: randMat(4, 2), alpha: 2, beta: -0.5},
+		{a: randMat(4, 3).T(), b: randMat(2, 4).T(), alpha: 1.5, beta: 1},
+		{a: randMat(2, 3), b: sym, alpha: 1, beta: 1},
+		{a: sym, b: randMat(3, 2), alpha: -1, beta: 2},
+		{a: randMat(2, 3), b: tri, alpha: 1, beta: 1},
+		{a: tri.T(), b: randMat(3, 3), alpha: 0.5, beta: 1},
+		{a: randMat(3, 3), b: NewVector(3, []float64{1, -2, 3}), alpha: 1, beta: 1},
+		{a: randMat(3, 4), b: randMat(4, 2), alpha: 3, beta: 0},
+	} {
+		ar, _ := test.a.Dims()
+		_, bc := test.b.Dims()
+		c := randMat(ar, bc)
+
+		var want Dense
+		want.Mul(test.a, test.b)
+		want.Scale(test.alpha, &want)
+		var scaled Dense
+		scaled.Scale(test.beta, c)
+		want.Add(&want, &scaled)
+
+		c.MulTo(test.alpha, test.a, test.b, test.beta)
+		if !EqualApprox(c, &want, 1e-12) {
+			t.Errorf("unexpected result for test %d:\ngot:\n% v\nwant:\n% v", i, Formatted(c), Formatted(&want))
+		}
+	}
+
+	// A zero beta ignores the receiver, including any NaN values.
+	a := randMat(3, 3)
+	c := NewDense(3, 3, nil)
+	for i := 0; i < 3; i++ {
+		c.Set(i, i, math.NaN())
+	}
+	c.MulTo(2, a, a, 0)
+	var want Dense
+	want.Mul(a, a)
+	want.Scale(2, &want)
+	if !EqualApprox(c, &want, 1e-12) {
+		t.Errorf("unexpected result for zero beta:\ngot:\n% v\nwant:\n% v", Formatted(c), Formatted(&want))
+	}
+
+	// The receiver may be one of the operands.
+	a = randMat(3, 3)
+	b := randMat(3, 3)
+	want.Mul(a, b)
+	want.Add(&want, a)
+	a.MulTo(1, a, b, 1)
+	if !EqualApprox(a, &want, 1e-12) {
+		t.Errorf("unexpected result for aliased receiver:\ngot:\n% v\nwant:\n% v", Formatted(a), Formatted(&want))
+	}
+
+	for _, fn := range []func(){
+		func() { NewDense(2, 2, nil).MulTo(1, randMat(2, 3), randMat(2, 2), 0) },
+		func() { NewDense(2, 3, nil).MulTo(1, randMat(2, 3), randMat(3, 2), 1) },
+		func() { new(Dense).MulTo(1, randMat(2, 3), randMat(3, 2), 1) },
+	} {
+		if p, _ := panics(fn); !p {
+			t.Error("expected panic for shape mismatch")
+		}
+	}
+}
+
 func TestExp(t *testing.T) {
 	for i, test := range []struct {
 		a    [][]float64
@@ -1097,6 +1429,86 @@ func TestPowN(t *testing.T) {
 	}
 }
 
+func TestPowNegative(t *testing.T) {
+	for i, a := range []Matrix{
+		NewDense(1, 1, []float64{-4}),
+		NewDense(2, 2, []float64{
+			4, 7,
+			2, 6,
+		}),
+		NewDense(3, 3, []float64{
+			2, -1, 0,
+			-1, 2, -1,
+			0, -1, 2,
+		}),
+		NewDense(3, 3, []float64{
+			1, 2, 0,
+			0, 1, 3,
+			4, 0, 1,
+		}).T(),
+	} {
+		var inv Dense
+		if err := inv.Inverse(a); err != nil {
+			t.Fatalf("unexpected error inverting matrix for test %d: %v", i, err)
+		}
+
+		var got, want Dense
+		got.Pow(a, -1)
+		if !EqualApprox(&got, &inv, 1e-14) {
+			t.Errorf("unexpected result for Pow(a, -1) for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(&got), Formatted(&inv))
+		}
+		got.Pow(a, -2)
+		want.Mul(&inv, &inv)
+		if !EqualApprox(&got, &want, 1e-14) {
+			t.Errorf("unexpected result for Pow(a, -2) for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(&got), Formatted(&want))
+		}
+		got.Pow(a, -5)
+		want.iterativePow(&inv, 5)
+		if !EqualApprox(&got, &want, 1e-12) {
+			t.Errorf("unexpected result for Pow(a, -5) for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(&got), Formatted(&want))
+		}
+
+		// Check that a^-n * a^n is the identity.
+		var pos Dense
+		got.Pow(a, -3)
+		pos.Pow(a, 3)
+		got.Mul(&got, &pos)
+		n, _ := a.Dims()
+		eye := NewDense(n, n, nil)
+		for j := 0; j < n; j++ {
+			eye.Set(j, j, 1)
+		}
+		if !EqualApprox(&got, eye, 1e-10) {
+			t.Errorf("a^-3 * a^3 is not the identity for test %d:\n%v", i, Formatted(&got))
+		}
+
+		// The receiver may alias the input.
+		c := DenseCopyOf(a)
+		c.Pow(c, -1)
+		if !EqualApprox(c, &inv, 1e-14) {
+			t.Errorf("unexpected result for aliased Pow(a, -1) for test %d", i)
+		}
+	}
+
+	singular := NewDense(2, 2, []float64{
+		1, 2,
+		2, 4,
+	})
+	panicked, message := panics(func() { new(Dense).Pow(singular, -1) })
+	if !panicked || message != matrix.ErrSingular.Error() {
+		t.Errorf("unexpected panic for singular matrix: got: %q want: %q", message, matrix.ErrSingular)
+	}
+
+	// The most negative int cannot be negated, but an involution
+	// raised to any even power is the identity.
+	const minInt = -int(^uint(0)>>1) - 1
+	var got Dense
+	got.Pow(NewDense(2, 2, []float64{0, 1, 1, 0}), minInt)
+	if want := NewDense(2, 2, []float64{1, 0, 0, 1}); !Equal(&got, want) {
+		t.Errorf("unexpected result for most negative power:\ngot:\n%v\nwant:\n%v", Formatted(&got), Formatted(want))
+	}
+}
+
 func (m *Dense) iterativePow(a Matrix, n int) {
 	m.Clone(a)
 	for i := 1; i < n; i++ {
@@ -1302,6 +1714,388 @@ func TestApply(t *testing.T) {
 	}
 }
 
+func TestNormalizeRows(t *testing.T) {
+	for i, test := range []struct {
+		a    [][]float64
+		p    float64
+		want [][]float64
+	}{
+		{
+			a:    [][]float64{{3, 4}, {0, 0}, {-5, 12}},
+			p:    2,
+			want: [][]float64{{0.6, 0.8}, {0, 0}, {-5.0 / 13, 12.0 / 13}},
+		},
+		{
+			a:    [][]float64{{1, -3}, {2, 2}},
+			p:    1,
+			want: [][]float64{{0.25, -0.75}, {0.5, 0.5}},
+		},
+		{
+			a:    [][]float64{{1, -4, 2}, {0, 0, 0}},
+			p:    math.Inf(1),
+			want: [][]float64{{0.25, -1, 0.5}, {0, 0, 0}},
+		},
+	} {
+		var got Dense
+		got.NormalizeRows(NewDense(flatten(test.a)), test.p)
+		if !EqualApprox(&got, NewDense(flatten(test.want)), 1e-14) {
+			t.Errorf("unexpected result for NormalizeRows test %d: got: %v want: %v", i, got.mat.Data, test.want)
+		}
+	}
+
+	a, _ := randDense(10, 1, rand.NormFloat64)
+	a.SetRow(3, make([]float64, 10))
+	var m Dense
+	m.NormalizeRows(a, 2)
+	for i := 0; i < 10; i++ {
+		norm := floats.Norm(m.RawRowView(i), 2)
+		if i == 3 {
+			if norm != 0 {
+				t.Errorf("unexpected norm for zero row: got: %v want: 0", norm)
+			}
+			continue
+		}
+		if math.Abs(norm-1) > 1e-14 {
+			t.Errorf("unexpected norm for row %d: got: %v want: 1", i, norm)
+		}
+	}
+
+	// Normalizing in place must give the same result.
+	a.NormalizeRows(a, 2)
+	if !Equal(a, &m) {
+		t.Errorf("unexpected result for in-place NormalizeRows")
+	}
+
+	panicked, message := panics(func() { m.NormalizeRows(a, 0) })
+	if !panicked || message != matrix.ErrNormOrder.Error() {
+		t.Errorf("expected panic for zero norm order")
+	}
+}
+
+func TestNormalizeCols(t *testing.T) {
+	a := NewDense(3, 2, []float64{
+		3, 0,
+		4, 0,
+		0, 0,
+	})
+	var got Dense
+	got.NormalizeCols(a, 2)
+	want := NewDense(3, 2, []float64{
+		0.6, 0,
+		0.8, 0,
+		0, 0,
+	})
+	if !EqualApprox(&got, want, 1e-14) {
+		t.Errorf("unexpected result for NormalizeCols: got: %v want: %v", got.mat.Data, want.mat.Data)
+	}
+
+	b, _ := randDense(10, 1, rand.NormFloat64)
+	got.Reset()
+	got.NormalizeCols(b.T(), 2)
+	var wantT Dense
+	wantT.NormalizeRows(b, 2)
+	if !EqualApprox(&got, wantT.T(), 1e-14) {
+		t.Errorf("unexpected result for NormalizeCols of transpose")
+	}
+}
+
+func TestImputeColMean(t *testing.T) {
+	nan := math.NaN()
+	for i, test := range []struct {
+		a, mask, want *Dense
+	}{
+		{
+			// One missing entry in each column.
+			a: NewDense(4, 3, []float64{
+				1, nan, 3,
+				nan, 5, 6,
+				7, 8, nan,
+				10, 11, 12,
+			}),
+			mask: NewDense(4, 3, []float64{
+				1, 0, 1,
+				0, 1, 1,
+				1, 1, 0,
+				1, 1, 1,
+			}),
+			want: NewDense(4, 3, []float64{
+				1, 8, 3,
+				6, 5, 6,
+				7, 8, 7,
+				10, 11, 12,
+			}),
+		},
+		{
+			// Masked values are replaced even if they are not NaN,
+			// and a column with no present values is filled with zero.
+			a: NewDense(2, 2, []float64{
+				1, 100,
+				-3, nan,
+			}),
+			mask: NewDense(2, 2, []float64{
+				2, 0,
+				-1, 0,
+			}),
+			want: NewDense(2, 2, []float64{
+				1, 0,
+				-3, 0,
+			}),
+		},
+		{
+			a:    NewDense(1, 1, []float64{5}),
+			mask: NewDense(1, 1, []float64{1}),
+			want: NewDense(1, 1, []float64{5}),
+		},
+	} {
+		var got Dense
+		got.ImputeColMean(test.a, test.mask)
+		if !Equal(&got, test.want) {
+			t.Errorf("unexpected result for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(&got), Formatted(test.want))
+		}
+
+		// The receiver may be a or mask.
+		a := DenseCopyOf(test.a)
+		a.ImputeColMean(a, test.mask)
+		if !Equal(a, test.want) {
+			t.Errorf("unexpected result for test %d with receiver aliasing a:\ngot:\n%v\nwant:\n%v", i, Formatted(a), Formatted(test.want))
+		}
+		mask := DenseCopyOf(test.mask)
+		mask.ImputeColMean(test.a, mask)
+		if !Equal(mask, test.want) {
+			t.Errorf("unexpected result for test %d with receiver aliasing mask:\ngot:\n%v\nwant:\n%v", i, Formatted(mask), Formatted(test.want))
+		}
+	}
+
+	panicked, message := panics(func() { new(Dense).ImputeColMean(NewDense(2, 3, nil), NewDense(3, 2, nil)) })
+	if !panicked || message != matrix.ErrShape.Error() {
+		t.Errorf("unexpected panic for mismatched mask: got: %q want: %q", message, matrix.ErrShape)
+	}
+}
+
+func TestRescale(t *testing.T) {
+	for i, test := range []struct {
+		a              Matrix
+		newMin, newMax float64
+		want           *Dense
+	}{
+		{
+			a: NewDense(2, 3, []float64{
+				1, 2, 3,
+				4, 5, 6,
+			}),
+			newMin: 0,
+			newMax: 1,
+			want: NewDense(2, 3, []float64{
+				0, 0.2, 0.4,
+				0.6, 0.8, 1,
+			}),
+		},
+		{
+			a: NewDense(2, 2, []float64{
+				-1, 3,
+				1, 0,
+			}).T(),
+			newMin: -2,
+			newMax: 6,
+			want: NewDense(2, 2, []float64{
+				-2, 2,
+				6, 0,
+			}),
+		},
+		{
+			// The target range may be reversed.
+			a:      NewDense(1, 3, []float64{0, 5, 10}),
+			newMin: 1,
+			newMax: -1,
+			want:   NewDense(1, 3, []float64{1, 0, -1}),
+		},
+		{
+			// A constant matrix maps to newMin.
+			a:      NewDense(2, 2, []float64{7, 7, 7, 7}),
+			newMin: 3,
+			newMax: 4,
+			want:   NewDense(2, 2, []float64{3, 3, 3, 3}),
+		},
+	} {
+		var got Dense
+		got.Rescale(test.a, test.newMin, test.newMax)
+		if !EqualApprox(&got, test.want, 1e-14) {
+			t.Errorf("unexpected result for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(&got), Formatted(test.want))
+		}
+	}
+
+	for _, test := range []struct {
+		r, c           int
+		newMin, newMax float64
+	}{
+		{1, 2, 0, 1},
+		{5, 5, -1, 1},
+		{10, 3, 0.1, 0.7},
+		{4, 7, -100, 1e6},
+	} {
+		a := NewDense(test.r, test.c, nil)
+		for i := 0; i < test.r; i++ {
+			for j := 0; j < test.c; j++ {
+				a.Set(i, j, 1e3*rand.NormFloat64())
+			}
+		}
+		a.Rescale(a, test.newMin, test.newMax)
+		if got := Min(a); got != test.newMin {
+			t.Errorf("unexpected minimum for %d×%d: got: %v want: %v", test.r, test.c, got, test.newMin)
+		}
+		if got := Max(a); got != test.newMax {
+			t.Errorf("unexpected maximum for %d×%d: got: %v want: %v", test.r, test.c, got, test.newMax)
+		}
+	}
+}
+
+func TestMinMaxScaleCols(t *testing.T) {
+	for i, test := range []struct {
+		a              Matrix
+		newMin, newMax float64
+		want           *Dense
+	}{
+		{
+			a: NewDense(3, 3, []float64{
+				1, -10, 4,
+				2, 10, 4,
+				3, 0, 4,
+			}),
+			newMin: 0,
+			newMax: 1,
+			// The constant last column maps to newMin.
+			want: NewDense(3, 3, []float64{
+				0, 0, 0,
+				0.5, 1, 0,
+				1, 0.5, 0,
+			}),
+		},
+		{
+			a: NewDense(2, 3, []float64{
+				1, 5, 9,
+				3, 5, 3,
+			}).T(),
+			newMin: -1,
+			newMax: 1,
+			want: NewDense(3, 2, []float64{
+				-1, -1,
+				0, 1,
+				1, -1,
+			}),
+		},
+	} {
+		var got Dense
+		got.MinMaxScaleCols(test.a, test.newMin, test.newMax)
+		if !EqualApprox(&got, test.want, 1e-14) {
+			t.Errorf("unexpected result for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(&got), Formatted(test.want))
+		}
+	}
+
+	for _, test := range []struct {
+		r, c           int
+		newMin, newMax float64
+	}{
+		{2, 1, 0, 1},
+		{5, 5, -1, 1},
+		{10, 3, 0.1, 0.7},
+		{4, 7, -100, 1e6},
+	} {
+		a := NewDense(test.r, test.c, nil)
+		for i := 0; i < test.r; i++ {
+			for j := 0; j < test.c; j++ {
+				// Give each column a different scale and offset.
+				a.Set(i, j, float64(j+1)*rand.NormFloat64()+float64(10*j))
+			}
+		}
+		a.MinMaxScaleCols(a, test.newMin, test.newMax)
+		col := make([]float64, test.r)
+		for j := 0; j < test.c; j++ {
+			Col(col, j, a)
+			if got := floats.Min(col); got != test.newMin {
+				t.Errorf("unexpected minimum of column %d for %d×%d: got: %v want: %v", j, test.r, test.c, got, test.newMin)
+			}
+			if got := floats.Max(col); got != test.newMax {
+				t.Errorf("unexpected maximum of column %d for %d×%d: got: %v want: %v", j, test.r, test.c, got, test.newMax)
+			}
+		}
+	}
+}
+
+func TestQuantileNormalize(t *testing.T) {
+	for i, test := range []struct {
+		a    Matrix
+		want *Dense
+	}{
+		{
+			// The reference distribution is {2, 3, 14/3, 17/3}. The
+			// second column has a tie at ranks 3 and 4, each of which
+			// is replaced by (14/3 + 17/3) / 2 = 31/6.
+			a: NewDense(4, 3, []float64{
+				5, 4, 3,
+				2, 1, 4,
+				3, 4, 6,
+				4, 2, 8,
+			}),
+			want: NewDense(4, 3, []float64{
+				17.0 / 3, 31.0 / 6, 2,
+				2, 2, 3,
+				3, 31.0 / 6, 14.0 / 3,
+				14.0 / 3, 3, 17.0 / 3,
+			}),
+		},
+		{
+			// Columns with the same ranks become equal.
+			a: NewDense(3, 2, []float64{
+				1, 10,
+				3, 30,
+				2, 20,
+			}).T(),
+			want: NewDense(2, 3, []float64{
+				2, 2, 2,
+				20, 20, 20,
+			}),
+		},
+		{
+			// A single column is unchanged.
+			a:    NewDense(3, 1, []float64{3, -1, 2}),
+			want: NewDense(3, 1, []float64{3, -1, 2}),
+		},
+	} {
+		var got Dense
+		got.QuantileNormalize(test.a)
+		if !EqualApprox(&got, test.want, 1e-14) {
+			t.Errorf("unexpected result for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(&got), Formatted(test.want))
+		}
+
+		a := DenseCopyOf(test.a)
+		a.QuantileNormalize(a)
+		if !EqualApprox(a, test.want, 1e-14) {
+			t.Errorf("unexpected result for test %d with receiver aliasing a:\ngot:\n%v\nwant:\n%v", i, Formatted(a), Formatted(test.want))
+		}
+	}
+
+	// Without ties, every column of the result is a permutation of
+	// the same values.
+	const r, c = 20, 5
+	a := NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			a.Set(i, j, float64(j+1)*rand.NormFloat64())
+		}
+	}
+	var got Dense
+	got.QuantileNormalize(a)
+	want := Col(nil, 0, &got)
+	sort.Float64s(want)
+	for j := 1; j < c; j++ {
+		col := Col(nil, j, &got)
+		sort.Float64s(col)
+		if !floats.Equal(col, want) {
+			t.Errorf("column %d has a different distribution: got: %v want: %v", j, col, want)
+		}
+	}
+}
+
 func TestClone(t *testing.T) {
 	for i, test := range []struct {
 		a    [][]float64
@@ -1353,6 +2147,81 @@ func TestCopyPanic(t *testing.T) {
 	}
 }
 
+func TestCopyBlock(t *testing.T) {
+	for i, test := range []struct {
+		i, j int
+		src  Matrix
+		n    int
+		want *Dense
+	}{
+		{
+			i: 1, j: 1,
+			src: NewDense(2, 2, []float64{1, 2, 3, 4}),
+			n:   4,
+			want: NewDense(4, 4, []float64{
+				0, 0, 0, 0,
+				0, 1, 2, 0,
+				0, 3, 4, 0,
+				0, 0, 0, 0,
+			}),
+		},
+		{
+			i: 2, j: 3,
+			src: NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6}),
+			n:   2,
+			want: NewDense(4, 4, []float64{
+				0, 0, 0, 0,
+				0, 0, 0, 0,
+				0, 0, 0, 1,
+				0, 0, 0, 3,
+			}),
+		},
+		{
+			i: 0, j: 1,
+			src: NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}).T(),
+			n:   6,
+			want: NewDense(4, 4, []float64{
+				0, 1, 4, 0,
+				0, 2, 5, 0,
+				0, 3, 6, 0,
+				0, 0, 0, 0,
+			}),
+		},
+	} {
+		m := NewDense(4, 4, nil)
+		n := m.CopyBlock(test.i, test.j, test.src)
+		if n != test.n {
+			t.Errorf("unexpected number of copied elements for test %d: got: %d want: %d", i, n, test.n)
+		}
+		if !Equal(m, test.want) {
+			t.Errorf("unexpected result for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(m), Formatted(test.want))
+		}
+	}
+
+	// Copying the receiver into itself must use the original values.
+	m := NewDense(3, 3, []float64{
+		1, 2, 3,
+		4, 5, 6,
+		7, 8, 9,
+	})
+	m.CopyBlock(1, 1, m)
+	want := NewDense(3, 3, []float64{
+		1, 2, 3,
+		4, 1, 2,
+		7, 4, 5,
+	})
+	if !Equal(m, want) {
+		t.Errorf("unexpected result for self copy:\ngot:\n%v\nwant:\n%v", Formatted(m), Formatted(want))
+	}
+
+	for _, idx := range [][2]int{{-1, 0}, {4, 0}, {0, -1}, {0, 4}} {
+		m := NewDense(4, 4, nil)
+		if panicked, _ := panics(func() { m.CopyBlock(idx[0], idx[1], NewDense(1, 1, nil)) }); !panicked {
+			t.Errorf("expected panic for start position %v", idx)
+		}
+	}
+}
+
 func TestStack(t *testing.T) {
 	for i, test := range []struct {
 		a, b, e [][]float64
@@ -1570,6 +2439,405 @@ func TestOuter(t *testing.T) {
 	}
 }
 
+func TestTensorProduct(t *testing.T) {
+	for i, test := range []struct {
+		a, b Matrix
+	}{
+		{
+			a: NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}),
+			b: NewDense(2, 2, []float64{7, 8, 9, 10}),
+		},
+		{
+			a: NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}).T(),
+			b: NewDense(1, 3, []float64{-1, 0.5, 2}),
+		},
+		{
+			a: NewVector(3, []float64{1, 2, 3}),
+			b: NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6}).T(),
+		},
+	} {
+		ar, ac := test.a.Dims()
+		br, bc := test.b.Dims()
+		var m Dense
+		m.TensorProduct(test.a, test.b)
+		r, c := m.Dims()
+		if r != ar*ac || c != br*bc {
+			t.Errorf("unexpected shape for TensorProduct test %d: got: %d×%d want: %d×%d", i, r, c, ar*ac, br*bc)
+			continue
+		}
+		for ia := 0; ia < ar; ia++ {
+			for ja := 0; ja < ac; ja++ {
+				for kb := 0; kb < br; kb++ {
+					for lb := 0; lb < bc; lb++ {
+						row, col := TensorIndex(ar, ac, br, bc, ia, ja, kb, lb)
+						if row/ac != ia || row%ac != ja || col/bc != kb || col%bc != lb {
+							t.Errorf("unexpected inverse mapping for TensorIndex test %d", i)
+						}
+						got := m.At(row, col)
+						want := test.a.At(ia, ja) * test.b.At(kb, lb)
+						if got != want {
+							t.Errorf("unexpected element for TensorProduct test %d at {%d,%d,%d,%d}: got: %v want: %v",
+								i, ia, ja, kb, lb, got, want)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	a := NewDense(2, 2, []float64{1, 2, 3, 4})
+	panicked, message := panics(func() { a.TensorProduct(a, a) })
+	if !panicked || message != matrix.ErrShape.Error() {
+		t.Errorf("expected shape panic for aliased receiver")
+	}
+
+	for _, idx := range [][4]int{
+		{-1, 0, 0, 0},
+		{2, 0, 0, 0},
+		{0, -1, 0, 0},
+		{0, 3, 0, 0},
+		{0, 0, -1, 0},
+		{0, 0, 4, 0},
+		{0, 0, 0, -1},
+		{0, 0, 0, 5},
+	} {
+		panicked, message := panics(func() { TensorIndex(2, 3, 4, 5, idx[0], idx[1], idx[2], idx[3]) })
+		if !panicked || message != matrix.ErrIndexOutOfRange.Error() {
+			t.Errorf("unexpected panic for TensorIndex with indices %v: got: %q want: %q", idx, message, matrix.ErrIndexOutOfRange)
+		}
+	}
+
+	col := NewDense(2, 1, []float64{1, 2})
+	big := NewDense(10, 10, nil)
+	for _, test := range []struct {
+		fn   func()
+		want string
+	}{
+		{func() { a.TensorProduct(a.View(0, 0, 1, 2), col) }, regionOverlap},
+		{func() { a.TensorProduct(col, a.View(0, 0, 1, 2).T()) }, regionOverlap},
+		{func() { big.View(0, 0, 4, 4).(*Dense).TensorProduct(big.View(1, 1, 2, 2), a) }, regionOverlap},
+		{func() { big.View(0, 0, 4, 4).(*Dense).TensorProduct(a, big.View(2, 2, 2, 2).T()) }, regionOverlap},
+	} {
+		panicked, message := panics(test.fn)
+		if !panicked || message != test.want {
+			t.Errorf("unexpected panic for aliased receiver: got: %q want: %q", message, test.want)
+		}
+	}
+}
+
+func TestKronecker(t *testing.T) {
+	for i, test := range []struct {
+		a, b Matrix
+		want *Dense
+	}{
+		{
+			a: NewDense(1, 2, []float64{1, 2}),
+			b: NewDense(2, 1, []float64{3, 4}),
+			want: NewDense(2, 2, []float64{
+				3, 6,
+				4, 8,
+			}),
+		},
+		{
+			a: NewDense(2, 3, []float64{
+				1, 0, -1,
+				2, 3, 0,
+			}),
+			b: NewDense(2, 2, []float64{
+				1, 2,
+				3, 4,
+			}),
+			want: NewDense(4, 6, []float64{
+				1, 2, 0, 0, -1, -2,
+				3, 4, 0, 0, -3, -4,
+				2, 4, 3, 6, 0, 0,
+				6, 8, 9, 12, 0, 0,
+			}),
+		},
+		{
+			a: NewDense(2, 1, []float64{1, -1}).T(),
+			b: NewDense(2, 3, []float64{
+				1, 2, 3,
+				4, 5, 6,
+			}).T(),
+			want: NewDense(3, 4, []float64{
+				1, 4, -1, -4,
+				2, 5, -2, -5,
+				3, 6, -3, -6,
+			}),
+		},
+		{
+			a:    NewDense(1, 1, []float64{2}),
+			b:    NewDense(1, 1, []float64{-3}),
+			want: NewDense(1, 1, []float64{-6}),
+		},
+	} {
+		var got Dense
+		got.Kronecker(test.a, test.b)
+		if !Equal(&got, test.want) {
+			t.Errorf("unexpected Kronecker product for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(&got), Formatted(test.want))
+		}
+
+		// Check that a non-zero receiver is overwritten.
+		r, c := test.want.Dims()
+		m := NewDense(r, c, nil)
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				m.Set(i, j, 100)
+			}
+		}
+		m.Kronecker(test.a, test.b)
+		if !Equal(m, test.want) {
+			t.Errorf("unexpected Kronecker product into non-zero receiver for test %d", i)
+		}
+	}
+
+	a := NewDense(2, 3, []float64{
+		1, 2, 3,
+		4, 5, 6,
+	})
+	eye := NewDense(3, 3, []float64{
+		1, 0, 0,
+		0, 1, 0,
+		0, 0, 1,
+	})
+
+	// I ⊗ A is block diagonal with copies of A.
+	var got Dense
+	got.Kronecker(eye, a)
+	if want := BlockDiag(a, a, a); !Equal(&got, want) {
+		t.Errorf("unexpected I⊗A:\ngot:\n%v\nwant:\n%v", Formatted(&got), Formatted(want))
+	}
+
+	// A ⊗ I has a[i][j] on the diagonal of block (i, j).
+	got.Reset()
+	got.Kronecker(a, eye)
+	for i := 0; i < 6; i++ {
+		for j := 0; j < 9; j++ {
+			var want float64
+			if i%3 == j%3 {
+				want = a.At(i/3, j/3)
+			}
+			if v := got.At(i, j); v != want {
+				t.Errorf("unexpected A⊗I element at (%d,%d): got: %v want: %v", i, j, v, want)
+			}
+		}
+	}
+
+	// The mixed product property (A⊗B)(C⊗D) = (AC)⊗(BD).
+	b := NewDense(2, 2, []float64{1, -1, 2, 0})
+	c := NewDense(3, 2, []float64{1, 0, 2, 1, 0, 3})
+	d := NewDense(2, 1, []float64{3, -2})
+	var ab, cd, lhs, ac, bd, rhs Dense
+	ab.Kronecker(a, b)
+	cd.Kronecker(c, d)
+	lhs.Mul(&ab, &cd)
+	ac.Mul(a, c)
+	bd.Mul(b, d)
+	rhs.Kronecker(&ac, &bd)
+	if !EqualApprox(&lhs, &rhs, 1e-12) {
+		t.Errorf("mixed product property does not hold:\ngot:\n%v\nwant:\n%v", Formatted(&lhs), Formatted(&rhs))
+	}
+
+	// Shape mismatch and aliasing.
+	if p, msg := panics(func() { NewDense(2, 2, nil).Kronecker(a, b) }); !p || msg != matrix.ErrShape.Error() {
+		t.Errorf("unexpected panic for mismatched receiver: got: %q want: %q", msg, matrix.ErrShape)
+	}
+	big := NewDense(4, 4, nil)
+	small := big.View(0, 0, 2, 2)
+	if p, msg := panics(func() { big.Kronecker(small, b) }); !p || msg != regionOverlap {
+		t.Errorf("unexpected panic for receiver overlapping a: got: %q want: %q", msg, regionOverlap)
+	}
+	if p, msg := panics(func() { big.Kronecker(b, small.T()) }); !p || msg != regionOverlap {
+		t.Errorf("unexpected panic for receiver overlapping b: got: %q want: %q", msg, regionOverlap)
+	}
+	unit := NewDense(1, 1, []float64{2})
+	if p, msg := panics(func() { b.Kronecker(b, unit) }); !p || msg != regionIdentity {
+		t.Errorf("unexpected panic for receiver identical to a: got: %q want: %q", msg, regionIdentity)
+	}
+}
+
+func TestKroneckerSum(t *testing.T) {
+	// kron returns the Kronecker product of a and b.
+	kron := func(a, b Matrix) *Dense {
+		ar, ac := a.Dims()
+		br, bc := b.Dims()
+		k := NewDense(ar*br, ac*bc, nil)
+		for i := 0; i < ar; i++ {
+			for j := 0; j < ac; j++ {
+				for r := 0; r < br; r++ {
+					for c := 0; c < bc; c++ {
+						k.Set(i*br+r, j*bc+c, a.At(i, j)*b.At(r, c))
+					}
+				}
+			}
+		}
+		return k
+	}
+	eye := func(n int) *Dense {
+		d := NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			d.Set(i, i, 1)
+		}
+		return d
+	}
+
+	for i, test := range []struct {
+		a, b Matrix
+	}{
+		{
+			a: NewDense(1, 1, []float64{2}),
+			b: NewDense(2, 2, []float64{1, 2, 3, 4}),
+		},
+		{
+			a: NewDense(2, 2, []float64{1, -2, 3, 0}),
+			b: NewDense(3, 3, []float64{1, 2, 3, 4, 5, 6, 7, 8, 9}),
+		},
+		{
+			a: NewDense(3, 3, []float64{1, 2, 3, 4, 5, 6, 7, 8, 9}).T(),
+			b: NewSymDense(2, []float64{2, -1, -1, 2}),
+		},
+	} {
+		n, _ := test.a.Dims()
+		p, _ := test.b.Dims()
+		var want Dense
+		want.Add(kron(test.a, eye(p)), kron(eye(n), test.b))
+
+		// Use a non-zero receiver to check that it is overwritten.
+		m := NewDense(n*p, n*p, nil)
+		for r := 0; r < n*p; r++ {
+			for c := 0; c < n*p; c++ {
+				m.Set(r, c, 100)
+			}
+		}
+		m.KroneckerSum(test.a, test.b)
+		if !Equal(m, &want) {
+			t.Errorf("unexpected Kronecker sum for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(m), Formatted(&want))
+		}
+	}
+
+	a := NewDense(2, 2, []float64{1, 2, 3, 4})
+	for _, test := range []struct {
+		fn   func()
+		want error
+	}{
+		{func() { new(Dense).KroneckerSum(NewDense(2, 3, nil), a) }, matrix.ErrSquare},
+		{func() { new(Dense).KroneckerSum(a, NewDense(3, 2, nil)) }, matrix.ErrSquare},
+		{func() { a.KroneckerSum(a, a) }, matrix.ErrShape},
+	} {
+		panicked, message := panics(test.fn)
+		if !panicked || message != test.want.Error() {
+			t.Errorf("unexpected panic: got: %q want: %q", message, test.want)
+		}
+	}
+
+	// Aliasing.
+	unit := NewDense(1, 1, []float64{2})
+	big := NewDense(10, 10, nil)
+	for _, test := range []struct {
+		fn   func()
+		want string
+	}{
+		{func() { a.KroneckerSum(unit, a) }, regionIdentity},
+		{func() { a.KroneckerSum(unit, a.T()) }, regionIdentity},
+		{func() { big.View(0, 0, 4, 4).(*Dense).KroneckerSum(big.View(1, 1, 2, 2), a) }, regionOverlap},
+		{func() { big.View(0, 0, 4, 4).(*Dense).KroneckerSum(a, big.View(2, 2, 2, 2).T()) }, regionOverlap},
+	} {
+		panicked, message := panics(test.fn)
+		if !panicked || message != test.want {
+			t.Errorf("unexpected panic for aliased receiver: got: %q want: %q", message, test.want)
+		}
+	}
+}
+
+func TestHessianish(t *testing.T) {
+	// f(x) = [x0^2 * x1, 5*x0 + sin(x1), x0*x1*x2]
+	f := func(x *Vector) *Vector {
+		x0, x1, x2 := x.At(0, 0), x.At(1, 0), x.At(2, 0)
+		return NewVector(3, []float64{x0 * x0 * x1, 5*x0 + math.Sin(x1), x0 * x1 * x2})
+	}
+	jac := func(x []float64) *Dense {
+		x0, x1, x2 := x[0], x[1], x[2]
+		return NewDense(3, 3, []float64{
+			2 * x0 * x1, x0 * x0, 0,
+			5, math.Cos(x1), 0,
+			x1 * x2, x0 * x2, x0 * x1,
+		})
+	}
+	for _, x := range [][]float64{
+		{1, 2, 3},
+		{-0.5, 0.25, 4},
+	} {
+		orig := make([]float64, len(x))
+		copy(orig, x)
+		var m Dense
+		m.Hessianish(f, NewVector(len(x), x), 1e-6)
+		if !EqualApprox(&m, jac(x), 1e-8) {
+			t.Errorf("unexpected Jacobian at %v: got: %v want: %v", x, m.mat.Data, jac(x).mat.Data)
+		}
+		if !floats.Equal(x, orig) {
+			t.Errorf("x modified by Hessianish: got: %v want: %v", x, orig)
+		}
+	}
+
+	// The Jacobian of the gradient of a quadratic form is its Hessian.
+	a := NewSymDense(2, []float64{4, 1, 1, 3})
+	grad := func(x *Vector) *Vector {
+		var g Vector
+		g.MulVec(a, x)
+		g.ScaleVec(2, &g)
+		return &g
+	}
+	var h Dense
+	h.Hessianish(grad, NewVector(2, []float64{0.3, -7}), 1e-4)
+	var want Dense
+	want.Scale(2, a)
+	if !EqualApprox(&h, &want, 1e-10) {
+		t.Errorf("unexpected Hessian: got: %v want: %v", h.mat.Data, want.mat.Data)
+	}
+
+	// x must be restored when Hessianish panics.
+	x := []float64{1, 2, 3}
+	for _, test := range []struct {
+		name string
+		fn   func()
+		want string
+	}{
+		{
+			name: "mis-sized receiver",
+			fn:   func() { NewDense(2, 2, nil).Hessianish(f, NewVector(3, x), 1e-6) },
+			want: matrix.ErrShape.Error(),
+		},
+		{
+			name: "panicking gradient",
+			fn: func() {
+				var m Dense
+				m.Hessianish(func(v *Vector) *Vector {
+					if v.At(1, 0) != 2 {
+						panic("grad")
+					}
+					return f(v)
+				}, NewVector(3, x), 1e-6)
+			},
+			want: "grad",
+		},
+		{
+			name: "non-positive step",
+			fn:   func() { new(Dense).Hessianish(f, NewVector(3, x), 0) },
+			want: badFiniteStep,
+		},
+	} {
+		panicked, message := panics(test.fn)
+		if !panicked || message != test.want {
+			t.Errorf("unexpected panic for %s: got: %q want: %q", test.name, message, test.want)
+		}
+		if !floats.Equal(x, []float64{1, 2, 3}) {
+			t.Errorf("x modified by panicking Hessianish for %s: got: %v", test.name, x)
+			x = []float64{1, 2, 3}
+		}
+	}
+}
+
 func TestInverse(t *testing.T) {
 	for i, test := range []struct {
 		a    *Dense
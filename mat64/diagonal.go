@@ -0,0 +1,93 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import "github.com/gonum/matrix"
+
+var (
+	diagDense *DiagDense
+	_         Matrix    = diagDense
+	_         Symmetric = diagDense
+)
+
+// DiagDense represents a diagonal matrix in dense storage format. Only the
+// diagonal elements are stored.
+type DiagDense struct {
+	data []float64
+}
+
+// NewDiagDense creates a new n×n diagonal matrix. If data == nil, a new slice
+// is allocated for the diagonal. If len(data) == n, data is used as the
+// backing slice, and changes to the elements of the returned DiagDense will
+// be reflected in data. If neither of these is true, NewDiagDense will panic
+// with matrix.ErrShape.
+func NewDiagDense(n int, data []float64) *DiagDense {
+	if n < 0 {
+		panic("mat64: negative dimension")
+	}
+	if data == nil {
+		data = make([]float64, n)
+	}
+	if len(data) != n {
+		panic(matrix.ErrShape)
+	}
+	return &DiagDense{data: data}
+}
+
+// Dims returns the dimensions of the matrix.
+func (d *DiagDense) Dims() (r, c int) {
+	return len(d.data), len(d.data)
+}
+
+// Symmetric implements the Symmetric interface.
+func (d *DiagDense) Symmetric() int {
+	return len(d.data)
+}
+
+// T implements the Matrix interface. Diagonal matrices, by definition, are
+// equal to their transpose, and this is a no-op.
+func (d *DiagDense) T() Matrix {
+	return d
+}
+
+// Reset zeros the dimensions of the matrix so that it can be reused as the
+// receiver of a dimensionally restricted operation.
+//
+// See the Reseter interface for more information.
+func (d *DiagDense) Reset() {
+	d.data = d.data[:0]
+}
+
+func (d *DiagDense) isZero() bool {
+	return len(d.data) == 0
+}
+
+// DiagFrom copies the main diagonal of a into the receiver. If a is r×c, the
+// diagonal has min(r, c) elements. If the receiver is zero it is resized to
+// hold the diagonal, otherwise DiagFrom will panic with matrix.ErrShape if
+// the receiver is not min(r, c)×min(r, c).
+func (d *DiagDense) DiagFrom(a Matrix) {
+	r, c := a.Dims()
+	n := min(r, c)
+	if d.isZero() {
+		d.data = use(d.data, n)
+	} else if len(d.data) != n {
+		panic(matrix.ErrShape)
+	}
+
+	switch a := a.(type) {
+	case *DiagDense:
+		copy(d.data, a.data)
+	case RawMatrixer:
+		amat := a.RawMatrix()
+		for i := range d.data {
+			d.data[i] = amat.Data[i*amat.Stride+i]
+		}
+	default:
+		for i := range d.data {
+			d.data[i] = a.At(i, i)
+		}
+	}
+}
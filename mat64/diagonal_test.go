@@ -0,0 +1,205 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/matrix"
+)
+
+func TestNewDiagDense(t *testing.T) {
+	data := []float64{1, -2, 3}
+	d := NewDiagDense(3, data)
+	want := NewDense(3, 3, []float64{
+		1, 0, 0,
+		0, -2, 0,
+		0, 0, 3,
+	})
+	if r, c := d.Dims(); r != 3 || c != 3 {
+		t.Errorf("unexpected dimensions: got: %d×%d want: 3×3", r, c)
+	}
+	if n := d.Symmetric(); n != 3 {
+		t.Errorf("unexpected symmetric size: got: %d want: 3", n)
+	}
+	if !Equal(d, want) {
+		t.Errorf("unexpected diagonal matrix:\ngot:\n%v\nwant:\n%v", Formatted(d), Formatted(want))
+	}
+	if d.T() != Matrix(d) {
+		t.Error("transpose of diagonal matrix is not the receiver")
+	}
+
+	d.SetDiag(1, 5)
+	if data[1] != 5 {
+		t.Errorf("SetDiag not reflected in backing data: got: %v", data)
+	}
+	want.Set(1, 1, 5)
+	if !Equal(d, want) {
+		t.Errorf("unexpected diagonal matrix after SetDiag:\ngot:\n%v\nwant:\n%v", Formatted(d), Formatted(want))
+	}
+
+	if !Equal(NewDiagDense(2, nil), NewDense(2, 2, nil)) {
+		t.Error("unexpected non-zero element in new diagonal matrix")
+	}
+
+	for _, test := range []struct {
+		fn   func()
+		want string
+	}{
+		{fn: func() { NewDiagDense(2, []float64{1}) }, want: matrix.ErrShape.Error()},
+		{fn: func() { NewDiagDense(-1, nil) }, want: "mat64: negative dimension"},
+		{fn: func() { d.At(3, 0) }, want: matrix.ErrRowAccess.Error()},
+		{fn: func() { d.At(0, -1) }, want: matrix.ErrColAccess.Error()},
+		{fn: func() { d.SetDiag(3, 1) }, want: matrix.ErrIndexOutOfRange.Error()},
+		{fn: func() { d.SetDiag(-1, 1) }, want: matrix.ErrIndexOutOfRange.Error()},
+	} {
+		panicked, message := panics(test.fn)
+		if !panicked || message != test.want {
+			t.Errorf("unexpected panic: got: %q want: %q", message, test.want)
+		}
+	}
+}
+
+func TestDiagFrom(t *testing.T) {
+	for i, test := range []struct {
+		a    Matrix
+		want []float64
+	}{
+		{
+			a: NewDense(3, 3, []float64{
+				1, 2, 3,
+				4, 5, 6,
+				7, 8, 9,
+			}),
+			want: []float64{1, 5, 9},
+		},
+		{
+			a: NewDense(2, 3, []float64{
+				1, 2, 3,
+				4, 5, 6,
+			}),
+			want: []float64{1, 5},
+		},
+		{
+			a: NewDense(3, 2, []float64{
+				1, 2,
+				3, 4,
+				5, 6,
+			}).T(),
+			want: []float64{1, 4},
+		},
+		{
+			a: NewDense(4, 4, []float64{
+				1, 2, 3, 4,
+				5, 6, 7, 8,
+				9, 10, 11, 12,
+				13, 14, 15, 16,
+			}).View(1, 1, 3, 2),
+			want: []float64{6, 11},
+		},
+		{
+			a:    NewSymDense(2, []float64{1, 2, 2, 3}),
+			want: []float64{1, 3},
+		},
+		{
+			a:    NewDiagDense(3, []float64{-1, 0, 4}),
+			want: []float64{-1, 0, 4},
+		},
+	} {
+		var d DiagDense
+		d.DiagFrom(test.a)
+		if !Equal(&d, NewDiagDense(len(test.want), test.want)) {
+			t.Errorf("unexpected diagonal for test %d: got: %v want: %v", i, d.data, test.want)
+		}
+
+		// A correctly sized receiver is overwritten.
+		d.DiagFrom(test.a)
+		if !Equal(&d, NewDiagDense(len(test.want), test.want)) {
+			t.Errorf("unexpected diagonal for reused receiver in test %d: got: %v want: %v", i, d.data, test.want)
+		}
+	}
+
+	d := NewDiagDense(2, nil)
+	panicked, message := panics(func() { d.DiagFrom(NewDense(3, 3, nil)) })
+	if !panicked || message != matrix.ErrShape.Error() {
+		t.Errorf("unexpected panic for mismatched receiver: got: %q want: %q", message, matrix.ErrShape)
+	}
+	d.Reset()
+	d.DiagFrom(NewDense(3, 4, nil))
+	if r, c := d.Dims(); r != 3 || c != 3 {
+		t.Errorf("unexpected dimensions after Reset: got: %d×%d want: 3×3", r, c)
+	}
+}
+
+func TestDiagDenseMul(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	randDense := func(r, c int) *Dense {
+		m := NewDense(r, c, nil)
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				m.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		return m
+	}
+	d := NewDiagDense(3, []float64{2, -1, 0.5})
+	dd := DenseCopyOf(d)
+
+	for _, test := range []struct {
+		a, b   Matrix
+		da, db Matrix
+	}{
+		{a: d, b: randDense(3, 4), da: dd},
+		{a: d, b: randDense(4, 3).T(), da: dd},
+		{a: randDense(4, 3), b: d, db: dd},
+		{a: randDense(3, 4).T(), b: d, db: dd},
+		{a: d, b: NewSymDense(3, []float64{1, 2, 3, 2, 4, 5, 3, 5, 6}), da: dd},
+		{a: d, b: d, da: dd, db: dd},
+		{a: d.T(), b: randDense(3, 2), da: dd},
+	} {
+		da, db := test.da, test.db
+		if da == nil {
+			da = test.a
+		}
+		if db == nil {
+			db = test.b
+		}
+		var got, want Dense
+		got.Mul(test.a, test.b)
+		want.Mul(da, db)
+		if !EqualApprox(&got, &want, 1e-14) {
+			t.Errorf("unexpected product:\ngot:\n%v\nwant:\n%v", Formatted(&got), Formatted(&want))
+		}
+	}
+
+	// The receiver may be the dense operand.
+	b := randDense(3, 3)
+	var want Dense
+	want.Mul(dd, b)
+	b.Mul(d, b)
+	if !EqualApprox(b, &want, 1e-14) {
+		t.Errorf("unexpected product with receiver as right operand:\ngot:\n%v\nwant:\n%v", Formatted(b), Formatted(&want))
+	}
+	a := randDense(3, 3)
+	want.Mul(a, dd)
+	a.Mul(a, d)
+	if !EqualApprox(a, &want, 1e-14) {
+		t.Errorf("unexpected product with receiver as left operand:\ngot:\n%v\nwant:\n%v", Formatted(a), Formatted(&want))
+	}
+
+	// Partial overlap with the dense operand is not allowed.
+	m := NewDense(6, 6, nil)
+	panicked, message := panics(func() {
+		m.View(0, 0, 3, 3).(*Dense).Mul(d, m.View(1, 1, 3, 3))
+	})
+	if !panicked || message != regionOverlap {
+		t.Errorf("unexpected panic for overlapping operand: got: %q want: %q", message, regionOverlap)
+	}
+	panicked, message = panics(func() { new(Dense).Mul(d, NewDense(4, 3, nil)) })
+	if !panicked || message != matrix.ErrShape.Error() {
+		t.Errorf("unexpected panic for mismatched dimensions: got: %q want: %q", message, matrix.ErrShape)
+	}
+}
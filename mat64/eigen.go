@@ -8,9 +8,14 @@ package mat64
 import (
 	"math"
 
+	"github.com/gonum/blas/blas64"
+	"github.com/gonum/lapack"
+	"github.com/gonum/lapack/lapack64"
 	"github.com/gonum/matrix"
 )
 
+const badNoVect = "mat64: eigenvectors not computed"
+
 func symmetric(m *Dense) bool {
 	n, _ := m.Dims()
 	for i := 0; i < n; i++ {
@@ -37,22 +42,65 @@ type Eigen struct {
 //  A = P * D * P^-1
 // where D is a diagonal matrix containing the eigenvalues of the matrix, and
 // P is a matrix of the eigenvectors of A. If the vectors input argument is
-// false, the eigenvectors are not computed.
+// false, the eigenvectors are not computed and Vectors and ComplexVector
+// will panic.
+//
+// If a is exactly symmetric, its eigenvalues are real and are found in
+// ascending order using the symmetric tridiagonal QL algorithm, and the
+// eigenvectors are orthonormal. The eigenvectors of a symmetric matrix are
+// always computed. Otherwise, the decomposition is computed by the LAPACK
+// routine Dgeev. Complex eigenvalues of a general real matrix occur in
+// conjugate pairs that are stored consecutively, with the eigenvalue with
+// positive imaginary part first, and each eigenvector is normalized to have
+// unit Euclidean norm.
 //
 // Factorize returns whether the decomposition succeeded. If the decomposition
 // failed, routines that require a successful factorization will panic.
-//
-// BUG: The current implementation always computes the eigenvectors.
 func (e *Eigen) Factorize(a Matrix, vectors bool) (ok bool) {
-	// TODO(btracey): Replace this with a lapack-based implementation.
 	r, c := a.Dims()
 	if r != c {
 		panic(matrix.ErrShape)
 	}
 	aCopy := DenseCopyOf(a)
-	e.vectorsComputed = vectors
+	if symmetric(aCopy) {
+		e.ef = eigen(aCopy, 1e-16)
+		e.vectorsComputed = true
+		e.n = r
+		return true
+	}
+
+	wr := make([]float64, r)
+	wi := make([]float64, r)
+	jobvr := lapack.RightEVJob(lapack.None)
+	var vr blas64.General
+	if vectors {
+		jobvr = lapack.ComputeRightEV
+		vr = blas64.General{
+			Rows:   r,
+			Cols:   r,
+			Stride: r,
+			Data:   make([]float64, r*r),
+		}
+	}
+	jobvl := lapack.LeftEVJob(lapack.None)
+	var vl blas64.General
+
+	work := make([]float64, 1)
+	lapack64.Geev(jobvl, jobvr, aCopy.mat, wr, wi, vl, vr, work, -1)
+	work = make([]float64, int(work[0]))
+	first := lapack64.Geev(jobvl, jobvr, aCopy.mat, wr, wi, vl, vr, work, len(work))
+	if first != 0 {
+		e.n = 0
+		e.vectorsComputed = false
+		e.ef = eigenFactors{}
+		return false
+	}
 
-	e.ef = eigen(aCopy, 1e-16)
+	e.ef = eigenFactors{d: wr, e: wi}
+	if vectors {
+		e.ef.V = &Dense{mat: vr, capRows: r, capCols: r}
+	}
+	e.vectorsComputed = vectors
 	e.n = r
 	return true
 }
@@ -74,18 +122,207 @@ func (e *Eigen) Values(dst []complex128) []complex128 {
 	return dst
 }
 
-// Vectors returns the eigenvectors of the decomposition.
+// Vectors returns the eigenvectors of the decomposition in LAPACK's packed
+// real form. Vectors will panic if the eigenvectors were not computed.
 //
 // This signature and behavior will change when issue #308 is resolved.
 //
+// If the eigenvalue j is real, column j of v is its eigenvector. If the
+// eigenvalues j and j+1 are a complex conjugate pair, with the positive
+// imaginary part first, columns j and j+1 of v hold the real and imaginary
+// parts of the eigenvector of eigenvalue j, and the eigenvector of eigenvalue
+// j+1 is its complex conjugate. ComplexVector unpacks this form.
+//
 // The columns of v represent the eigenvectors in the sense that a*v = v*D,
-// i.e. a.v equals v.D. The matrix v may be badly conditioned, or even
+// i.e. a.v equals v.D, where D is block diagonal with the real eigenvalues
+// in 1-by-1 blocks and each complex pair, lambda ± i*mu, in a 2-by-2 block
+// [lambda, mu; -mu, lambda]. The matrix v may be badly conditioned, or even
 // singular, so the validity of the equation a = v*D*inverse(v) depends
 // upon the 2-norm condition number of v.
 func (e *Eigen) Vectors() *Dense {
+	if !e.vectorsComputed {
+		panic(badNoVect)
+	}
 	return DenseCopyOf(e.ef.V)
 }
 
+// ComplexVector returns the eigenvector corresponding to the eigenvalue j as a
+// complex vector, unpacking the real form described in Vectors. If dst is
+// non-nil, the eigenvector is stored in-place into dst, which must have
+// length n, otherwise ComplexVector will panic. If dst is nil, a new slice is
+// allocated. ComplexVector will panic if the eigenvectors were not computed,
+// or if j is not a valid eigenvalue index.
+func (e *Eigen) ComplexVector(j int, dst []complex128) []complex128 {
+	if !e.vectorsComputed {
+		panic(badNoVect)
+	}
+	if j < 0 || e.n <= j {
+		panic(matrix.ErrColAccess)
+	}
+	if dst == nil {
+		dst = make([]complex128, e.n)
+	}
+	if len(dst) != e.n {
+		panic(matrix.ErrSliceLengthMismatch)
+	}
+	v := e.ef.V
+	switch im := e.ef.e[j]; {
+	case im == 0:
+		for i := range dst {
+			dst[i] = complex(v.at(i, j), 0)
+		}
+	case im > 0:
+		for i := range dst {
+			dst[i] = complex(v.at(i, j), v.at(i, j+1))
+		}
+	default:
+		for i := range dst {
+			dst[i] = complex(v.at(i, j-1), -v.at(i, j))
+		}
+	}
+	return dst
+}
+
+// CanonicalizeSigns fixes the arbitrary signs of the eigenvectors so that the
+// largest magnitude element of each real eigenvector is positive, with ties
+// resolved in favor of the element with the lowest index. For a complex
+// conjugate pair of eigenvalues, the sign is determined by the real part of
+// the eigenvector, or by its imaginary part if the real part is zero, and the
+// real and imaginary parts are negated together. Applying CanonicalizeSigns
+// makes the vectors reproducible across factorizations of the same matrix.
+func (e *Eigen) CanonicalizeSigns() {
+	if !e.vectorsComputed {
+		return
+	}
+	v := e.ef.V.mat
+	for j := 0; j < e.n; j++ {
+		width := 1
+		if e.ef.e[j] != 0 {
+			// Complex conjugate pairs are stored as the real and
+			// imaginary parts in adjacent columns.
+			width = 2
+		}
+		sign := canonicalSign(v.Data[j:], v.Rows, v.Stride)
+		if sign == 0 && width == 2 {
+			sign = canonicalSign(v.Data[j+1:], v.Rows, v.Stride)
+		}
+		if sign < 0 {
+			for i := 0; i < v.Rows; i++ {
+				row := v.Data[i*v.Stride+j : i*v.Stride+j+width]
+				for k := range row {
+					row[k] = -row[k]
+				}
+			}
+		}
+		j += width - 1
+	}
+}
+
+// EigenSym is a type for creating and using the eigenvalue decomposition of a
+// symmetric matrix.
+type EigenSym struct {
+	vectorsComputed bool
+
+	values  []float64
+	vectors *Dense
+}
+
+// Factorize computes the eigenvalue decomposition of the symmetric matrix a
+// using the LAPACK routine Dsyev. The decomposition is
+//  A = V * Λ * V^T
+// where Λ is a diagonal matrix of the real eigenvalues of A in ascending
+// order, and V is an orthonormal matrix whose columns are the corresponding
+// eigenvectors. Repeated eigenvalues are returned once per multiplicity, and
+// their eigenvectors span the eigenspace orthonormally. If the vectors input
+// argument is false, the eigenvectors are not computed and VectorsTo will
+// panic.
+//
+// Factorize returns whether the decomposition succeeded. If the decomposition
+// failed, routines that require a successful factorization will panic.
+func (e *EigenSym) Factorize(a Symmetric, vectors bool) (ok bool) {
+	n := a.Symmetric()
+	w := NewSymDense(n, nil)
+	w.CopySym(a)
+
+	jobz := lapack.EVJob(lapack.None)
+	if vectors {
+		jobz = lapack.ComputeEV
+	}
+	values := make([]float64, n)
+	work := make([]float64, 1)
+	lapack64.Syev(jobz, w.mat, values, work, -1)
+	work = make([]float64, int(work[0]))
+	ok = lapack64.Syev(jobz, w.mat, values, work, len(work))
+	if !ok {
+		e.vectorsComputed = false
+		e.values = nil
+		e.vectors = nil
+		return false
+	}
+	e.vectorsComputed = vectors
+	e.values = values
+	e.vectors = nil
+	if vectors {
+		// Dsyev overwrites the full storage of a with the eigenvectors.
+		e.vectors = &Dense{
+			mat: blas64.General{
+				Rows:   n,
+				Cols:   n,
+				Stride: w.mat.Stride,
+				Data:   w.mat.Data,
+			},
+			capRows: n,
+			capCols: n,
+		}
+	}
+	return true
+}
+
+// Values extracts the eigenvalues of the factorized matrix in ascending order.
+// If dst is non-nil, the values are stored in-place into dst. In this case dst
+// must have length n, otherwise Values will panic. If dst is nil, then a new
+// slice will be allocated of the proper length.
+func (e *EigenSym) Values(dst []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(e.values))
+	}
+	if len(dst) != len(e.values) {
+		panic(matrix.ErrSliceLengthMismatch)
+	}
+	copy(dst, e.values)
+	return dst
+}
+
+// VectorsTo stores the orthonormal eigenvectors of the factorized matrix into
+// the columns of dst, with column j the eigenvector of the jth value returned
+// by Values. VectorsTo will panic if the eigenvectors were not computed, or if
+// dst is not zero and is not n×n.
+func (e *EigenSym) VectorsTo(dst *Dense) {
+	if !e.vectorsComputed {
+		panic(badNoVect)
+	}
+	n := len(e.values)
+	dst.reuseAs(n, n)
+	dst.Copy(e.vectors)
+}
+
+// CanonicalizeSigns fixes the arbitrary signs of the eigenvectors so that the
+// largest magnitude element of each eigenvector is positive, with ties
+// resolved in favor of the element with the lowest index. Applying
+// CanonicalizeSigns makes the vectors reproducible across factorizations of
+// the same matrix.
+func (e *EigenSym) CanonicalizeSigns() {
+	if !e.vectorsComputed {
+		return
+	}
+	v := e.vectors.mat
+	for j := 0; j < v.Cols; j++ {
+		if canonicalSign(v.Data[j:], v.Rows, v.Stride) < 0 {
+			blas64.Scal(v.Rows, -1, blas64.Vector{Inc: v.Stride, Data: v.Data[j:]})
+		}
+	}
+}
+
 type eigenFactors struct {
 	V    *Dense
 	d, e []float64
@@ -6,8 +6,13 @@ package mat64
 
 import (
 	"math"
+	"math/cmplx"
+	"math/rand"
 	"reflect"
+	"sort"
 	"testing"
+
+	"github.com/gonum/floats"
 )
 
 func TestEigen(t *testing.T) {
@@ -109,3 +114,341 @@ func TestEigen(t *testing.T) {
 		}
 	}
 }
+
+func TestEigenCanonicalizeSigns(t *testing.T) {
+	for _, a := range []*Dense{
+		NewDense(3, 3, []float64{
+			4, 1, -2,
+			1, 3, 0,
+			-2, 0, 5,
+		}),
+		// This matrix has a complex conjugate pair of eigenvalues.
+		NewDense(3, 3, []float64{
+			0, -1, 2,
+			1, 0, -3,
+			0, 0, 2,
+		}),
+	} {
+		n, _ := a.Dims()
+		var e1, e2 Eigen
+		e1.Factorize(a, true)
+		e2.Factorize(a, true)
+		// Flip the sign of the first eigenvector of the second factorization
+		// and of the complex pair if there is one, leaving a valid
+		// decomposition of a.
+		v := e2.ef.V
+		for j := 0; j < n; j++ {
+			if j != 0 && e2.ef.e[j] == 0 {
+				continue
+			}
+			for i := 0; i < n; i++ {
+				v.Set(i, j, -v.At(i, j))
+			}
+		}
+
+		e1.CanonicalizeSigns()
+		e2.CanonicalizeSigns()
+		v1 := e1.Vectors()
+		if !Equal(v1, e2.Vectors()) {
+			t.Errorf("eigenvectors differ after sign canonicalization:\n% v\n% v", Formatted(v1), Formatted(e2.Vectors()))
+		}
+
+		// Check that a*v = v*D still holds.
+		d := NewDense(n, n, nil)
+		for j := 0; j < n; j++ {
+			d.Set(j, j, e1.ef.d[j])
+			if e1.ef.e[j] > 0 {
+				d.Set(j, j+1, e1.ef.e[j])
+			} else if e1.ef.e[j] < 0 {
+				d.Set(j, j-1, e1.ef.e[j])
+			}
+		}
+		var av, vd Dense
+		av.Mul(a, v1)
+		vd.Mul(v1, d)
+		if !EqualApprox(&av, &vd, 1e-12) {
+			t.Errorf("canonicalized eigenvectors do not satisfy a*v = v*D:\n% v\n% v", Formatted(&av), Formatted(&vd))
+		}
+	}
+}
+
+func TestEigenGeneral(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		a    *Dense
+		want []complex128
+	}{
+		{
+			// A rotation by π/2.
+			a: NewDense(2, 2, []float64{
+				0, -1,
+				1, 0,
+			}),
+			want: []complex128{1i, -1i},
+		},
+		{
+			a: NewDense(3, 3, []float64{
+				2, 0, 0,
+				0, 1, -2,
+				0, 2, 1,
+			}),
+			want: []complex128{1 + 2i, 1 - 2i, 2},
+		},
+		{
+			// Upper triangular, so the eigenvalues are the diagonal.
+			a: NewDense(3, 3, []float64{
+				1, 2, 3,
+				0, 4, 5,
+				0, 0, 6,
+			}),
+			want: []complex128{1, 4, 6},
+		},
+		{a: randNonsymmetric(rnd, 1)},
+		{a: randNonsymmetric(rnd, 5)},
+		{a: randNonsymmetric(rnd, 10)},
+		{a: randNonsymmetric(rnd, 30)},
+	} {
+		n, _ := test.a.Dims()
+		var e Eigen
+		if !e.Factorize(test.a, true) {
+			t.Errorf("unexpected factorization failure for %d×%d", n, n)
+			continue
+		}
+		values := e.Values(nil)
+		if test.want != nil {
+			for j, v := range values {
+				if cmplx.Abs(v-test.want[j]) > 1e-12 {
+					t.Errorf("unexpected eigenvalues: got: %v want: %v", values, test.want)
+					break
+				}
+			}
+		}
+
+		for j := 0; j < n; j++ {
+			if imag(values[j]) > 0 && (j+1 == n || values[j+1] != cmplx.Conj(values[j])) {
+				t.Errorf("complex eigenvalue %d not followed by its conjugate: %v", j, values)
+			}
+
+			// Check that a*v = λ*v for the unpacked eigenvector.
+			v := e.ComplexVector(j, nil)
+			var norm float64
+			for _, vi := range v {
+				norm += real(vi)*real(vi) + imag(vi)*imag(vi)
+			}
+			if math.Abs(norm-1) > 1e-12 {
+				t.Errorf("eigenvector %d of %d×%d not normalized: norm^2 = %v", j, n, n, norm)
+			}
+			for i := 0; i < n; i++ {
+				var av complex128
+				for k := 0; k < n; k++ {
+					av += complex(test.a.At(i, k), 0) * v[k]
+				}
+				if cmplx.Abs(av-values[j]*v[i]) > 1e-10 {
+					t.Errorf("eigenpair %d of %d×%d does not satisfy a*v = λ*v at row %d", j, n, n, i)
+					break
+				}
+			}
+		}
+
+		// The eigenvalues do not depend on whether the vectors are computed.
+		var noVec Eigen
+		if !noVec.Factorize(test.a, false) {
+			t.Errorf("unexpected factorization failure without vectors for %d×%d", n, n)
+			continue
+		}
+		got := noVec.Values(nil)
+		for j := range got {
+			if cmplx.Abs(got[j]-values[j]) > 1e-12 {
+				t.Errorf("eigenvalues without vectors differ for %d×%d: got: %v want: %v", n, n, got, values)
+				break
+			}
+		}
+		if symmetric(test.a) {
+			// Eigenvectors of symmetric matrices are always computed.
+			continue
+		}
+		if p, _ := panics(func() { noVec.Vectors() }); !p {
+			t.Error("expected panic from Vectors when vectors not computed")
+		}
+		if p, _ := panics(func() { noVec.ComplexVector(0, nil) }); !p {
+			t.Error("expected panic from ComplexVector when vectors not computed")
+		}
+	}
+}
+
+func randNonsymmetric(rnd *rand.Rand, n int) *Dense {
+	a := NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			a.Set(i, j, rnd.NormFloat64())
+		}
+	}
+	return a
+}
+
+func TestEigenSym(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		a    *SymDense
+		want []float64
+	}{
+		{
+			a:    NewSymDense(1, []float64{-3}),
+			want: []float64{-3},
+		},
+		{
+			a: NewSymDense(3, []float64{
+				2, 0, 0,
+				0, -1, 0,
+				0, 0, 5,
+			}),
+			want: []float64{-1, 2, 5},
+		},
+		{
+			a: NewSymDense(2, []float64{
+				2, 1,
+				1, 2,
+			}),
+			want: []float64{1, 3},
+		},
+		{
+			// Repeated eigenvalue with a two-dimensional eigenspace.
+			a: NewSymDense(3, []float64{
+				2, 1, 1,
+				1, 2, 1,
+				1, 1, 2,
+			}),
+			want: []float64{1, 1, 4},
+		},
+		{
+			// Every eigenvalue is repeated.
+			a: NewSymDense(4, []float64{
+				3, 0, 0, 0,
+				0, 3, 0, 0,
+				0, 0, 3, 0,
+				0, 0, 0, 3,
+			}),
+			want: []float64{3, 3, 3, 3},
+		},
+		{a: randSymmetric(rnd, 5)},
+		{a: randSymmetric(rnd, 20)},
+	} {
+		n := test.a.Symmetric()
+		var e EigenSym
+		if !e.Factorize(test.a, true) {
+			t.Errorf("unexpected factorization failure for n=%d", n)
+			continue
+		}
+		values := e.Values(nil)
+		if test.want != nil && !floats.EqualApprox(values, test.want, 1e-12) {
+			t.Errorf("unexpected eigenvalues for n=%d: got: %v want: %v", n, values, test.want)
+		}
+		if !sort.Float64sAreSorted(values) {
+			t.Errorf("eigenvalues not in ascending order for n=%d: %v", n, values)
+		}
+
+		var v Dense
+		e.VectorsTo(&v)
+
+		// Check that V is orthonormal.
+		var vtv Dense
+		vtv.Mul(v.T(), &v)
+		eye := NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			eye.Set(i, i, 1)
+		}
+		if !EqualApprox(&vtv, eye, 1e-12) {
+			t.Errorf("eigenvectors not orthonormal for n=%d", n)
+		}
+
+		// Check that A = V * Λ * V^T.
+		var got Dense
+		got.Clone(&v)
+		for j, l := range values {
+			for i := 0; i < n; i++ {
+				got.Set(i, j, got.At(i, j)*l)
+			}
+		}
+		got.Mul(&got, v.T())
+		if !EqualApprox(&got, test.a, 1e-12) {
+			t.Errorf("unexpected reconstruction for n=%d:\ngot:\n%v\nwant:\n%v", n, Formatted(&got), Formatted(test.a))
+		}
+
+		// The eigenvalues do not depend on whether the vectors are computed.
+		var noVec EigenSym
+		if !noVec.Factorize(test.a, false) {
+			t.Errorf("unexpected factorization failure without vectors for n=%d", n)
+			continue
+		}
+		if got := noVec.Values(nil); !floats.EqualApprox(got, values, 1e-12) {
+			t.Errorf("eigenvalues without vectors differ for n=%d: got: %v want: %v", n, got, values)
+		}
+		if p, _ := panics(func() { noVec.VectorsTo(&Dense{}) }); !p {
+			t.Error("expected panic from VectorsTo when vectors not computed")
+		}
+		if p, _ := panics(func() { e.Values(make([]float64, n+1)) }); !p {
+			t.Error("expected panic for mismatched destination length")
+		}
+	}
+}
+
+func TestEigenSymCanonicalizeSigns(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 3, 10} {
+		a := randSymmetric(rnd, n)
+		var e1, e2 EigenSym
+		if !e1.Factorize(a, true) || !e2.Factorize(a, true) {
+			t.Fatalf("unexpected factorization failure for n=%d", n)
+		}
+		// Flip the signs of some of the eigenvectors of the second
+		// factorization, leaving a valid decomposition of a.
+		v := e2.vectors
+		for j := 0; j < n; j += 2 {
+			for i := 0; i < n; i++ {
+				v.Set(i, j, -v.At(i, j))
+			}
+		}
+
+		e1.CanonicalizeSigns()
+		e2.CanonicalizeSigns()
+		var v1, v2 Dense
+		e1.VectorsTo(&v1)
+		e2.VectorsTo(&v2)
+		if !Equal(&v1, &v2) {
+			t.Errorf("eigenvectors differ after sign canonicalization for n=%d:\n% v\n% v", n, Formatted(&v1), Formatted(&v2))
+		}
+		for j := 0; j < n; j++ {
+			if canonicalSign(v1.mat.Data[j:], n, v1.mat.Stride) < 0 {
+				t.Errorf("largest element of eigenvector %d is negative for n=%d", j, n)
+			}
+		}
+
+		// Check that A = V * Λ * V^T still holds.
+		var got Dense
+		got.Clone(&v1)
+		for j, l := range e1.Values(nil) {
+			for i := 0; i < n; i++ {
+				got.Set(i, j, got.At(i, j)*l)
+			}
+		}
+		got.Mul(&got, v1.T())
+		if !EqualApprox(&got, a, 1e-12) {
+			t.Errorf("canonicalized eigenvectors do not reconstruct a for n=%d", n)
+		}
+	}
+
+	// Canonicalizing without vectors is a no-op.
+	var e EigenSym
+	e.Factorize(NewSymDense(2, []float64{1, 2, 2, 1}), false)
+	e.CanonicalizeSigns()
+}
+
+func randSymmetric(rnd *rand.Rand, n int) *SymDense {
+	a := NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			a.SetSym(i, j, rnd.NormFloat64())
+		}
+	}
+	return a
+}
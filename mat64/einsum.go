@@ -0,0 +1,194 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"strings"
+
+	"github.com/gonum/matrix"
+)
+
+// EinsumError is returned by Einsum when a specification is malformed or
+// is not supported.
+type EinsumError struct {
+	Spec   string
+	Reason string
+}
+
+func (e EinsumError) Error() string {
+	return "mat64: einsum " + `"` + e.Spec + `": ` + e.Reason
+}
+
+// Einsum evaluates the Einstein summation described by spec over the given
+// operands and returns the result. Einsum supports an explicit subset of the
+// NumPy notation: spec must have the form "in->out" or "in,in->out" with one
+// term of exactly two lower case letters for each operand and an output term
+// of at most two distinct letters, each of which must appear in an input term.
+// Letters that appear in the input terms but not in the output are summed
+// over. For example, "ij,jk->ik" is the matrix product, "ii->" is the trace,
+// "ij,ij->" is the sum of the element-wise product and "ij->ji" is the
+// transpose.
+//
+// An output term of two letters gives a matrix, one letter gives a column
+// vector and an empty output term gives a 1×1 matrix.
+//
+// Einsum returns an EinsumError if spec is malformed or unsupported, and
+// matrix.ErrShape if the operand dimensions labeled by the same letter
+// differ.
+func Einsum(spec string, operands ...Matrix) (*Dense, error) {
+	ins, out, reason := parseEinsum(spec)
+	if reason != "" {
+		return nil, EinsumError{Spec: spec, Reason: reason}
+	}
+	if len(ins) != len(operands) {
+		return nil, EinsumError{Spec: spec, Reason: "number of terms does not match number of operands"}
+	}
+
+	size := make(map[byte]int)
+	for k, term := range ins {
+		r, c := operands[k].Dims()
+		for l, n := range [2]int{r, c} {
+			if s, ok := size[term[l]]; ok && s != n {
+				return nil, matrix.ErrShape
+			}
+			size[term[l]] = n
+		}
+	}
+
+	if m, ok := einsumMul(ins, out, operands); ok {
+		return m, nil
+	}
+
+	// Order the letters with the output letters first so that the
+	// leading positions of idx give the output element.
+	letters := []byte(out)
+	for _, term := range ins {
+		for l := 0; l < 2; l++ {
+			if strings.IndexByte(string(letters), term[l]) < 0 {
+				letters = append(letters, term[l])
+			}
+		}
+	}
+	pos := make(map[byte]int, len(letters))
+	for i, l := range letters {
+		pos[l] = i
+	}
+
+	r, c := 1, 1
+	if len(out) > 0 {
+		r = size[out[0]]
+	}
+	if len(out) > 1 {
+		c = size[out[1]]
+	}
+	m := NewDense(r, c, nil)
+	idx := make([]int, len(letters))
+	for {
+		v := 1.0
+		for k, term := range ins {
+			v *= operands[k].At(idx[pos[term[0]]], idx[pos[term[1]]])
+		}
+		var i, j int
+		if len(out) > 0 {
+			i = idx[0]
+		}
+		if len(out) > 1 {
+			j = idx[1]
+		}
+		m.set(i, j, m.at(i, j)+v)
+
+		// Advance the index odometer, last letter fastest.
+		l := len(letters) - 1
+		for ; l >= 0; l-- {
+			idx[l]++
+			if idx[l] < size[letters[l]] {
+				break
+			}
+			idx[l] = 0
+		}
+		if l < 0 {
+			return m, nil
+		}
+	}
+}
+
+// parseEinsum splits spec into its input and output terms. If spec is
+// malformed or unsupported, parseEinsum returns a non-empty reason.
+func parseEinsum(spec string) (ins []string, out, reason string) {
+	parts := strings.Split(spec, "->")
+	if len(parts) != 2 {
+		return nil, "", `specification must contain exactly one "->"`
+	}
+	ins = strings.Split(parts[0], ",")
+	if len(ins) > 2 {
+		return nil, "", "at most two operands are supported"
+	}
+	seen := make(map[byte]bool)
+	for _, term := range ins {
+		if len(term) != 2 {
+			return nil, "", "input term " + `"` + term + `"` + " must have exactly two indices"
+		}
+		for l := 0; l < 2; l++ {
+			if term[l] < 'a' || 'z' < term[l] {
+				return nil, "", "indices must be lower case letters"
+			}
+			seen[term[l]] = true
+		}
+	}
+	out = parts[1]
+	if len(out) > 2 {
+		return nil, "", "output term must have at most two indices"
+	}
+	for l := 0; l < len(out); l++ {
+		if !seen[out[l]] {
+			return nil, "", "output index " + `"` + out[l:l+1] + `"` + " does not appear in the input"
+		}
+	}
+	if len(out) == 2 && out[0] == out[1] {
+		return nil, "", "output indices must be distinct"
+	}
+	return ins, out, ""
+}
+
+// einsumMul evaluates a two operand contraction over a single shared index
+// using matrix multiplication. The returned bool is false if the contraction
+// does not have that form.
+func einsumMul(ins []string, out string, operands []Matrix) (*Dense, bool) {
+	if len(ins) != 2 || len(out) != 2 {
+		return nil, false
+	}
+	ta, tb := ins[0], ins[1]
+	if ta[0] == ta[1] || tb[0] == tb[1] {
+		return nil, false
+	}
+	// Orient a so that the shared index is its column
+	// and b so that the shared index is its row.
+	a, b := operands[0], operands[1]
+	switch {
+	case ta[1] == tb[0]:
+	case ta[1] == tb[1]:
+		b, tb = b.T(), string([]byte{tb[1], tb[0]})
+	case ta[0] == tb[0]:
+		a, ta = a.T(), string([]byte{ta[1], ta[0]})
+	case ta[0] == tb[1]:
+		a, ta = a.T(), string([]byte{ta[1], ta[0]})
+		b, tb = b.T(), string([]byte{tb[1], tb[0]})
+	default:
+		return nil, false
+	}
+	if ta[0] == tb[1] {
+		return nil, false
+	}
+	var m Dense
+	switch out {
+	case ta[:1] + tb[1:]:
+		m.Mul(a, b)
+	case tb[1:] + ta[:1]:
+		m.Mul(b.T(), a.T())
+	default:
+		return nil, false
+	}
+	return &m, true
+}
@@ -0,0 +1,97 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"testing"
+
+	"github.com/gonum/matrix"
+)
+
+func TestEinsum(t *testing.T) {
+	a := NewDense(2, 3, []float64{
+		1, 2, 3,
+		4, 5, 6,
+	})
+	b := NewDense(3, 2, []float64{
+		7, 8,
+		9, 10,
+		11, 12,
+	})
+	c := NewDense(2, 3, []float64{
+		-1, 0, 2,
+		3, 1, -2,
+	})
+	sq := NewDense(3, 3, []float64{
+		1, 2, 3,
+		4, 5, 6,
+		7, 8, 9,
+	})
+	var ab, ba, abt Dense
+	ab.Mul(a, b)
+	ba.Mul(b, a)
+	abt.Mul(a, c.T())
+
+	for _, test := range []struct {
+		spec     string
+		operands []Matrix
+		want     *Dense
+	}{
+		{spec: "ij,jk->ik", operands: []Matrix{a, b}, want: &ab},
+		{spec: "ij,jk->ki", operands: []Matrix{a, b}, want: DenseCopyOf(ab.T())},
+		{spec: "jk,ij->ik", operands: []Matrix{a, b}, want: &ba},
+		{spec: "ij,kj->ik", operands: []Matrix{a, c}, want: &abt},
+		{spec: "ji,jk->ik", operands: []Matrix{a.T(), b}, want: &ab},
+		{spec: "ii->", operands: []Matrix{sq}, want: NewDense(1, 1, []float64{15})},
+		{spec: "ij,ij->", operands: []Matrix{a, c}, want: NewDense(1, 1, []float64{1*-1 + 3*2 + 4*3 + 5*1 + 6*-2})},
+		{spec: "ij->ji", operands: []Matrix{a}, want: DenseCopyOf(a.T())},
+		{spec: "ij->ij", operands: []Matrix{a}, want: a},
+		{spec: "ij->i", operands: []Matrix{a}, want: NewDense(2, 1, []float64{6, 15})},
+		{spec: "ii->i", operands: []Matrix{sq}, want: NewDense(3, 1, []float64{1, 5, 9})},
+		{spec: "ij,jk->", operands: []Matrix{a, b}, want: NewDense(1, 1, []float64{ab.At(0, 0) + ab.At(0, 1) + ab.At(1, 0) + ab.At(1, 1)})},
+	} {
+		got, err := Einsum(test.spec, test.operands...)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", test.spec, err)
+			continue
+		}
+		if !EqualApprox(got, test.want, 1e-12) {
+			t.Errorf("unexpected result for %q:\ngot:\n%v\nwant:\n%v", test.spec, Formatted(got), Formatted(test.want))
+		}
+	}
+
+	for _, test := range []struct {
+		spec     string
+		operands []Matrix
+	}{
+		{spec: "ij,jk", operands: []Matrix{a, b}},
+		{spec: "ij->ji->ij", operands: []Matrix{a}},
+		{spec: "ij,jk,kl->il", operands: []Matrix{a, b, a}},
+		{spec: "ijk->ij", operands: []Matrix{a}},
+		{spec: "iJ->i", operands: []Matrix{a}},
+		{spec: "ij->k", operands: []Matrix{a}},
+		{spec: "ij->ii", operands: []Matrix{sq}},
+		{spec: "ij->ijk", operands: []Matrix{a}},
+		{spec: "ij,jk->ik", operands: []Matrix{a}},
+	} {
+		_, err := Einsum(test.spec, test.operands...)
+		if _, ok := err.(EinsumError); !ok {
+			t.Errorf("expected EinsumError for %q: got: %v", test.spec, err)
+		}
+	}
+
+	for _, test := range []struct {
+		spec     string
+		operands []Matrix
+	}{
+		{spec: "ij,jk->ik", operands: []Matrix{a, a}},
+		{spec: "ii->", operands: []Matrix{a}},
+		{spec: "ij,ij->", operands: []Matrix{a, b}},
+	} {
+		if _, err := Einsum(test.spec, test.operands...); err != matrix.ErrShape {
+			t.Errorf("unexpected error for %q: got: %v want: %v", test.spec, err, matrix.ErrShape)
+		}
+	}
+}
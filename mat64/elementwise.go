@@ -0,0 +1,221 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+
+	"github.com/gonum/matrix"
+)
+
+// PowElem raises each element of a to the real power p, placing the result
+// in the receiver. PowElem is distinct from Pow, which computes the matrix
+// power. Following math.Pow, negative elements raised to a non-integer p
+// give NaN.
+func (m *Dense) PowElem(a Matrix, p float64) {
+	m.applyElem(a, func(v float64) float64 { return math.Pow(v, p) })
+}
+
+// Abs places the element-wise absolute value of a into the receiver.
+func (m *Dense) Abs(a Matrix) {
+	m.applyElem(a, math.Abs)
+}
+
+// SqrtElem places the element-wise square root of a into the receiver.
+// SqrtElem is distinct from a matrix square root. Negative elements give NaN.
+func (m *Dense) SqrtElem(a Matrix) {
+	m.applyElem(a, math.Sqrt)
+}
+
+// ExpElem places the element-wise exponential of a into the receiver.
+// ExpElem is distinct from Exp, which computes the matrix exponential.
+func (m *Dense) ExpElem(a Matrix) {
+	m.applyElem(a, math.Exp)
+}
+
+// LogElem places the element-wise natural logarithm of a into the receiver.
+// Zero elements give -Inf and negative elements give NaN.
+func (m *Dense) LogElem(a Matrix) {
+	m.applyElem(a, math.Log)
+}
+
+// Sigmoid places the element-wise logistic function, 1/(1+exp(-x)), of a
+// into the receiver. The result saturates to 0 and 1 for inputs of large
+// magnitude without overflow.
+func (m *Dense) Sigmoid(a Matrix) {
+	m.applyElem(a, sigmoid)
+}
+
+// sigmoid returns the logistic function of x, evaluating exp only for
+// non-positive arguments to avoid overflow.
+func sigmoid(x float64) float64 {
+	if x >= 0 {
+		return 1 / (1 + math.Exp(-x))
+	}
+	e := math.Exp(x)
+	return e / (1 + e)
+}
+
+// Tanh places the element-wise hyperbolic tangent of a into the receiver.
+func (m *Dense) Tanh(a Matrix) {
+	m.applyElem(a, math.Tanh)
+}
+
+// ReLU places the element-wise rectified linear function, max(0, x), of a
+// into the receiver.
+func (m *Dense) ReLU(a Matrix) {
+	m.applyElem(a, func(v float64) float64 {
+		if v < 0 {
+			return 0
+		}
+		return v
+	})
+}
+
+// LeakyReLU places the element-wise leaky rectified linear function of a into
+// the receiver. Non-negative elements are unchanged and negative elements are
+// multiplied by slope.
+func (m *Dense) LeakyReLU(a Matrix, slope float64) {
+	m.applyElem(a, func(v float64) float64 {
+		if v < 0 {
+			return slope * v
+		}
+		return v
+	})
+}
+
+// SigmoidGrad places the element-wise derivative of the logistic function
+// into the receiver, given the already activated values s = Sigmoid(x) in
+// activated. The derivative is computed as s·(1-s).
+func (m *Dense) SigmoidGrad(activated Matrix) {
+	m.applyElem(activated, func(s float64) float64 { return s * (1 - s) })
+}
+
+// TanhGrad places the element-wise derivative of the hyperbolic tangent into
+// the receiver, given the already activated values t = Tanh(x) in activated.
+// The derivative is computed as 1-t².
+func (m *Dense) TanhGrad(activated Matrix) {
+	m.applyElem(activated, func(t float64) float64 { return 1 - t*t })
+}
+
+// ReLUGrad places the element-wise derivative of the rectified linear function
+// into the receiver, given the already activated values r = ReLU(x) in
+// activated. The derivative is 1 where r is positive and 0 otherwise, taking
+// the derivative at zero to be 0.
+func (m *Dense) ReLUGrad(activated Matrix) {
+	m.applyElem(activated, func(r float64) float64 {
+		if r > 0 {
+			return 1
+		}
+		return 0
+	})
+}
+
+// CompareGreater places a mask of a into the receiver, with elements set to 1
+// where the corresponding element of a is greater than threshold and 0
+// otherwise.
+func (m *Dense) CompareGreater(a Matrix, threshold float64) {
+	m.applyElem(a, func(v float64) float64 { return indicator(v > threshold) })
+}
+
+// CompareLess places a mask of a into the receiver, with elements set to 1
+// where the corresponding element of a is less than threshold and 0
+// otherwise.
+func (m *Dense) CompareLess(a Matrix, threshold float64) {
+	m.applyElem(a, func(v float64) float64 { return indicator(v < threshold) })
+}
+
+// CompareEqual places a mask of a into the receiver, with elements set to 1
+// where the corresponding element of a is equal to threshold and 0 otherwise.
+func (m *Dense) CompareEqual(a Matrix, threshold float64) {
+	m.applyElem(a, func(v float64) float64 { return indicator(v == threshold) })
+}
+
+// indicator returns 1 if b is true and 0 otherwise.
+func indicator(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Where places into the receiver the elements of a where the corresponding
+// element of cond is non-zero and the elements of b otherwise. Where will
+// panic with matrix.ErrShape if cond, a and b do not have the same dimensions,
+// and will panic if the receiver partially overlaps any of them.
+func (m *Dense) Where(cond, a, b Matrix) {
+	r, c := cond.Dims()
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != r || ac != c || br != r || bc != c {
+		panic(matrix.ErrShape)
+	}
+
+	m.reuseAs(r, c)
+
+	// An operand that is the receiver, possibly transposed, is read
+	// from its original storage while the result is built in a
+	// workspace. Partial overlap with the receiver is not allowed.
+	var alias bool
+	for _, v := range []Matrix{cond, a, b} {
+		vU, _ := untranspose(v)
+		if vU == m {
+			alias = true
+			continue
+		}
+		if rm, ok := vU.(RawMatrixer); ok {
+			m.checkOverlap(rm.RawMatrix())
+		}
+	}
+	if alias {
+		var restore func()
+		m, restore = m.isolatedWorkspace(cond)
+		defer restore()
+	}
+
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if cond.At(i, j) != 0 {
+				m.set(i, j, a.At(i, j))
+			} else {
+				m.set(i, j, b.At(i, j))
+			}
+		}
+	}
+}
+
+// SanitizeNaN places a copy of a into the receiver with NaN and ±Inf elements
+// replaced by replacement, and returns the number of elements replaced. This
+// is useful for cleaning data before passing it to routines that require
+// finite input.
+func (m *Dense) SanitizeNaN(a Matrix, replacement float64) (count int) {
+	m.applyElem(a, func(v float64) float64 {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			count++
+			return replacement
+		}
+		return v
+	})
+	return count
+}
+
+// ReplaceNaN places a copy of a into the receiver with NaN elements replaced
+// by replacement, and returns the number of elements replaced. Unlike
+// SanitizeNaN, infinite elements are retained.
+func (m *Dense) ReplaceNaN(a Matrix, replacement float64) (count int) {
+	m.applyElem(a, func(v float64) float64 {
+		if math.IsNaN(v) {
+			count++
+			return replacement
+		}
+		return v
+	})
+	return count
+}
+
+// applyElem applies fn to each element of a, placing the result in the receiver.
+func (m *Dense) applyElem(a Matrix, fn func(float64) float64) {
+	m.Apply(func(_, _ int, v float64) float64 { return fn(v) }, a)
+}
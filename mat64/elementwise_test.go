@@ -0,0 +1,331 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/matrix"
+)
+
+func TestPowElem(t *testing.T) {
+	for _, a := range []Matrix{
+		NewDense(2, 3, []float64{-2, -1, 0, 1, 2.5, 3}),
+		NewDense(3, 2, []float64{-2, -1, 0, 1, 2.5, 3}).T(),
+		NewSymDense(2, []float64{1, -2, -2, 4}),
+	} {
+		var got, want Dense
+		got.PowElem(a, 2)
+		want.MulElem(a, a)
+		if !EqualApprox(&got, &want, 1e-14) {
+			t.Errorf("unexpected squared elements:\ngot:\n%v\nwant:\n%v", Formatted(&got), Formatted(&want))
+		}
+	}
+
+	// Operating in place must give the same result.
+	a := NewDense(2, 2, []float64{1, 4, 9, 16})
+	a.PowElem(a, 0.5)
+	if want := NewDense(2, 2, []float64{1, 2, 3, 4}); !Equal(a, want) {
+		t.Errorf("unexpected in place result:\ngot:\n%v\nwant:\n%v", Formatted(a), Formatted(want))
+	}
+
+	var neg Dense
+	neg.PowElem(NewDense(1, 2, []float64{-4, 4}), 0.5)
+	if !math.IsNaN(neg.At(0, 0)) || neg.At(0, 1) != 2 {
+		t.Errorf("unexpected result for negative base with non-integer power: got: %v", neg.RawRowView(0))
+	}
+}
+
+func TestElemFuncs(t *testing.T) {
+	a := NewDense(3, 3, []float64{
+		-2, -1, -0.5,
+		0, 0.25, 1,
+		2, 10, 1e-3,
+	})
+	for _, test := range []struct {
+		name string
+		fn   func(m *Dense, a Matrix)
+		ref  func(float64) float64
+	}{
+		{name: "Abs", fn: (*Dense).Abs, ref: math.Abs},
+		{name: "SqrtElem", fn: (*Dense).SqrtElem, ref: math.Sqrt},
+		{name: "ExpElem", fn: (*Dense).ExpElem, ref: math.Exp},
+		{name: "LogElem", fn: (*Dense).LogElem, ref: math.Log},
+	} {
+		for _, src := range []Matrix{a, a.T()} {
+			var got Dense
+			test.fn(&got, src)
+			r, c := src.Dims()
+			for i := 0; i < r; i++ {
+				for j := 0; j < c; j++ {
+					v, want := got.At(i, j), test.ref(src.At(i, j))
+					if v != want && !(math.IsNaN(v) && math.IsNaN(want)) {
+						t.Errorf("unexpected %s result at (%d, %d): got: %v want: %v", test.name, i, j, v, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSigmoidTanh(t *testing.T) {
+	a := NewDense(1, 7, []float64{math.Inf(-1), -1000, -1, 0, 1, 1000, math.Inf(1)})
+
+	var s Dense
+	s.Sigmoid(a)
+	want := []float64{0, 0, 1 / (1 + math.E), 0.5, 1 / (1 + 1/math.E), 1, 1}
+	for j, w := range want {
+		if v := s.At(0, j); math.IsNaN(v) || math.Abs(v-w) > 1e-15 {
+			t.Errorf("unexpected sigmoid of %v: got: %v want: %v", a.At(0, j), v, w)
+		}
+	}
+
+	var th Dense
+	th.Tanh(a)
+	for j := range want {
+		if v, w := th.At(0, j), math.Tanh(a.At(0, j)); v != w {
+			t.Errorf("unexpected tanh of %v: got: %v want: %v", a.At(0, j), v, w)
+		}
+	}
+}
+
+func TestReLU(t *testing.T) {
+	a := NewDense(2, 3, []float64{
+		-3, -0.5, 0,
+		0.5, 2, 7,
+	})
+	for _, test := range []struct {
+		slope float64
+		want  *Dense
+	}{
+		{
+			slope: 0,
+			want: NewDense(2, 3, []float64{
+				0, 0, 0,
+				0.5, 2, 7,
+			}),
+		},
+		{
+			slope: 0.1,
+			want: NewDense(2, 3, []float64{
+				-0.3, -0.05, 0,
+				0.5, 2, 7,
+			}),
+		},
+	} {
+		var got Dense
+		got.LeakyReLU(a, test.slope)
+		if !EqualApprox(&got, test.want, 1e-15) {
+			t.Errorf("unexpected leaky ReLU result for slope %v:\ngot:\n%v\nwant:\n%v", test.slope, Formatted(&got), Formatted(test.want))
+		}
+		if test.slope != 0 {
+			continue
+		}
+		got.Reset()
+		got.ReLU(a)
+		if !Equal(&got, test.want) {
+			t.Errorf("unexpected ReLU result:\ngot:\n%v\nwant:\n%v", Formatted(&got), Formatted(test.want))
+		}
+	}
+}
+
+func TestActivationGrad(t *testing.T) {
+	x := NewDense(2, 4, []float64{
+		-5, -1, -0.25, 0,
+		0.1, 0.5, 2, 6,
+	})
+	const h = 1e-6
+	var xp, xm Dense
+	xp.Apply(func(_, _ int, v float64) float64 { return v + h }, x)
+	xm.Apply(func(_, _ int, v float64) float64 { return v - h }, x)
+
+	for _, test := range []struct {
+		name string
+		fn   func(m *Dense, a Matrix)
+		grad func(m *Dense, activated Matrix)
+	}{
+		{name: "Sigmoid", fn: (*Dense).Sigmoid, grad: (*Dense).SigmoidGrad},
+		{name: "Tanh", fn: (*Dense).Tanh, grad: (*Dense).TanhGrad},
+	} {
+		var act, got, fp, fm, want Dense
+		test.fn(&act, x)
+		test.grad(&got, &act)
+		test.fn(&fp, &xp)
+		test.fn(&fm, &xm)
+		want.Sub(&fp, &fm)
+		want.Scale(1/(2*h), &want)
+		if !EqualApprox(&got, &want, 1e-8) {
+			t.Errorf("unexpected %s gradient:\ngot:\n%v\nwant:\n%v", test.name, Formatted(&got), Formatted(&want))
+		}
+	}
+
+	var act, got Dense
+	act.ReLU(x)
+	got.ReLUGrad(&act)
+	want := NewDense(2, 4, []float64{
+		0, 0, 0, 0,
+		1, 1, 1, 1,
+	})
+	if !Equal(&got, want) {
+		t.Errorf("unexpected ReLU gradient:\ngot:\n%v\nwant:\n%v", Formatted(&got), Formatted(want))
+	}
+}
+
+func TestCompare(t *testing.T) {
+	a := NewDense(2, 4, []float64{
+		-1, 0.5, 2, math.NaN(),
+		3, 2, 1.5, 7,
+	})
+	for _, test := range []struct {
+		name string
+		fn   func(m *Dense, a Matrix, threshold float64)
+		want *Dense
+	}{
+		{
+			name: "CompareGreater",
+			fn:   (*Dense).CompareGreater,
+			want: NewDense(2, 4, []float64{
+				0, 0, 0, 0,
+				1, 0, 0, 1,
+			}),
+		},
+		{
+			name: "CompareLess",
+			fn:   (*Dense).CompareLess,
+			want: NewDense(2, 4, []float64{
+				1, 1, 0, 0,
+				0, 0, 1, 0,
+			}),
+		},
+		{
+			name: "CompareEqual",
+			fn:   (*Dense).CompareEqual,
+			want: NewDense(2, 4, []float64{
+				0, 0, 1, 0,
+				0, 1, 0, 0,
+			}),
+		},
+	} {
+		var got Dense
+		test.fn(&got, a, 2)
+		if !Equal(&got, test.want) {
+			t.Errorf("unexpected %s mask:\ngot:\n%v\nwant:\n%v", test.name, Formatted(&got), Formatted(test.want))
+		}
+	}
+}
+
+func TestWhere(t *testing.T) {
+	x := NewDense(2, 3, []float64{
+		-1, 4, 0.5,
+		2, -3, 6,
+	})
+	y := NewDense(2, 3, []float64{
+		10, 20, 30,
+		40, 50, 60,
+	})
+
+	// Blend x and y, taking x where x is greater than one.
+	var mask, got Dense
+	mask.CompareGreater(x, 1)
+	got.Where(&mask, x, y)
+	want := NewDense(2, 3, []float64{
+		10, 4, 30,
+		2, 50, 6,
+	})
+	if !Equal(&got, want) {
+		t.Errorf("unexpected blend:\ngot:\n%v\nwant:\n%v", Formatted(&got), Formatted(want))
+	}
+
+	// Selecting into an operand must give the same result.
+	m := DenseCopyOf(y)
+	m.Where(&mask, x, m)
+	if !Equal(m, want) {
+		t.Errorf("unexpected in place blend:\ngot:\n%v\nwant:\n%v", Formatted(m), Formatted(want))
+	}
+
+	// Selecting from the transpose of the receiver must read
+	// the original elements.
+	sq := NewDense(2, 2, []float64{
+		1, 2,
+		3, 4,
+	})
+	ones := NewDense(2, 2, []float64{1, 1, 1, 1})
+	sq.Where(ones, sq.T(), ones)
+	want = NewDense(2, 2, []float64{
+		1, 3,
+		2, 4,
+	})
+	if !Equal(sq, want) {
+		t.Errorf("unexpected blend from transposed receiver:\ngot:\n%v\nwant:\n%v", Formatted(sq), Formatted(want))
+	}
+
+	panicked, message := panics(func() { got.Where(&mask, x, NewDense(3, 2, nil)) })
+	if !panicked || message != matrix.ErrShape.Error() {
+		t.Errorf("expected shape panic for mismatched operands")
+	}
+	panicked, message = panics(func() {
+		m := NewDense(10, 10, nil)
+		m.View(1, 1, 2, 2).(*Dense).Where(ones, m.View(2, 2, 2, 2), ones)
+	})
+	if !panicked || message != regionOverlap {
+		t.Errorf("unexpected panic for overlapping operand: got: %q want: %q", message, regionOverlap)
+	}
+}
+
+func TestSanitizeNaN(t *testing.T) {
+	nan := math.NaN()
+	inf := math.Inf(1)
+	a := NewDense(3, 3, []float64{
+		1, nan, 3,
+		inf, 5, -inf,
+		nan, 8, 9,
+	})
+
+	var m Dense
+	n := m.SanitizeNaN(a, 0)
+	if n != 4 {
+		t.Errorf("unexpected replacement count: got: %d want: 4", n)
+	}
+	want := NewDense(3, 3, []float64{
+		1, 0, 3,
+		0, 5, 0,
+		0, 8, 9,
+	})
+	if !Equal(&m, want) {
+		t.Errorf("unexpected sanitized matrix:\ngot:\n%v\nwant:\n%v", Formatted(&m), Formatted(want))
+	}
+
+	m.Reset()
+	n = m.ReplaceNaN(a.T(), -1)
+	if n != 2 {
+		t.Errorf("unexpected NaN replacement count: got: %d want: 2", n)
+	}
+	want = NewDense(3, 3, []float64{
+		1, inf, -1,
+		-1, 5, 8,
+		3, -inf, 9,
+	})
+	if !Equal(&m, want) {
+		t.Errorf("unexpected NaN replaced matrix:\ngot:\n%v\nwant:\n%v", Formatted(&m), Formatted(want))
+	}
+
+	// Sanitizing in place must give the same result.
+	n = a.SanitizeNaN(a, 7)
+	if n != 4 {
+		t.Errorf("unexpected in place replacement count: got: %d want: 4", n)
+	}
+	if n = a.SanitizeNaN(a, 7); n != 0 {
+		t.Errorf("unexpected replacement count for clean matrix: got: %d want: 0", n)
+	}
+	want = NewDense(3, 3, []float64{
+		1, 7, 3,
+		7, 5, 7,
+		7, 8, 9,
+	})
+	if !Equal(a, want) {
+		t.Errorf("unexpected in place sanitized matrix:\ngot:\n%v\nwant:\n%v", Formatted(a), Formatted(want))
+	}
+}
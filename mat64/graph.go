@@ -0,0 +1,186 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+
+	"github.com/gonum/matrix"
+)
+
+// Laplacian returns the graph Laplacian, L = D - A, of the graph with the
+// weighted adjacency matrix adj, where D is the diagonal matrix of the row
+// sums of adj. If normalized is true, the symmetric normalized Laplacian,
+// D^-1/2 (D - A) D^-1/2, is returned instead. Self-loops are included in
+// the degree of their node, so that a node with degree d and self-loop
+// weight w has a diagonal element of d-w, or 1-w/d when normalized. Nodes
+// with zero degree have zero rows and columns in the normalized Laplacian.
+//
+// If adj is a *CSR, the returned Laplacian is a *CSR with the pattern of adj
+// and the diagonal. Otherwise, if adj is symmetric, that is if it is a
+// Symmetric or its elements are symmetric, the returned Laplacian is a
+// *SymDense, and if not it is a *Dense of the out-degree Laplacian.
+//
+// Laplacian will panic with matrix.ErrSquare if adj is not square.
+func Laplacian(adj Matrix, normalized bool) Matrix {
+	n, c := adj.Dims()
+	if n != c {
+		panic(matrix.ErrSquare)
+	}
+
+	if a, ok := adj.(*CSR); ok {
+		return sparseLaplacian(a, normalized)
+	}
+
+	deg := make([]float64, n)
+	row := make([]float64, n)
+	for i := range deg {
+		for _, v := range Row(row, i, adj) {
+			deg[i] += v
+		}
+	}
+	scale := laplacianScale(deg, normalized)
+	elem := func(i, j int) float64 {
+		v := -adj.At(i, j)
+		if i == j {
+			v += deg[i]
+		}
+		return scale[i] * v * scale[j]
+	}
+
+	if isSymmetric(adj) {
+		l := NewSymDense(n, nil)
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				l.SetSym(i, j, elem(i, j))
+			}
+		}
+		return l
+	}
+	l := NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			l.set(i, j, elem(i, j))
+		}
+	}
+	return l
+}
+
+// SpectralEmbed returns an embedding of the nodes of the undirected graph with
+// the weighted adjacency matrix adj into dims dimensions. Row i of the returned
+// n×dims matrix holds the coordinates of node i, given by the eigenvectors of
+// the graph Laplacian that correspond to its dims smallest non-zero
+// eigenvalues. A graph with k connected components has k zero eigenvalues,
+// all of which are skipped.
+//
+// Sparse adjacency matrices are converted to dense storage for the eigenvalue
+// decomposition.
+//
+// SpectralEmbed returns matrix.ErrNotSymmetric if adj is not symmetric and
+// matrix.ErrShape if the Laplacian has fewer than dims non-zero eigenvalues.
+// SpectralEmbed will panic with matrix.ErrShape if dims is not positive and
+// with matrix.ErrSquare if adj is not square.
+func SpectralEmbed(adj Matrix, dims int) (*Dense, error) {
+	if dims <= 0 {
+		panic(matrix.ErrShape)
+	}
+	l := DenseCopyOf(Laplacian(adj, false))
+	if !symmetric(l) {
+		return nil, matrix.ErrNotSymmetric
+	}
+	n, _ := l.Dims()
+
+	var eig Eigen
+	if !eig.Factorize(l, true) {
+		return nil, matrix.ErrNoConvergence
+	}
+	// The eigenvalues of a symmetric matrix are real and
+	// are returned in ascending order.
+	vals := eig.Values(nil)
+	tol := float64(n) * epsilon * math.Abs(real(vals[n-1]))
+	first := 0
+	for first < n && real(vals[first]) <= tol {
+		first++
+	}
+	if first+dims > n {
+		return nil, matrix.ErrShape
+	}
+
+	var emb Dense
+	emb.Clone(eig.Vectors().View(0, first, n, dims))
+	return &emb, nil
+}
+
+// sparseLaplacian returns the Laplacian of the graph with adjacency matrix a.
+func sparseLaplacian(a *CSR, normalized bool) *CSR {
+	n := a.rows
+	deg := make([]float64, n)
+	for i := range deg {
+		for _, v := range a.data[a.indptr[i]:a.indptr[i+1]] {
+			deg[i] += v
+		}
+	}
+	scale := laplacianScale(deg, normalized)
+
+	l := &CSR{rows: n, cols: n, indptr: make([]int, n+1)}
+	for i := 0; i < n; i++ {
+		diag := false
+		for k := a.indptr[i]; k < a.indptr[i+1]; k++ {
+			j := a.ind[k]
+			if j > i && !diag {
+				l.ind = append(l.ind, i)
+				l.data = append(l.data, scale[i]*deg[i]*scale[i])
+				diag = true
+			}
+			v := -a.data[k]
+			if j == i {
+				v += deg[i]
+				diag = true
+			}
+			l.ind = append(l.ind, j)
+			l.data = append(l.data, scale[i]*v*scale[j])
+		}
+		if !diag {
+			l.ind = append(l.ind, i)
+			l.data = append(l.data, scale[i]*deg[i]*scale[i])
+		}
+		l.indptr[i+1] = len(l.ind)
+	}
+	return l
+}
+
+// laplacianScale returns the diagonal scaling applied to each side of the
+// Laplacian for the given node degrees.
+func laplacianScale(deg []float64, normalized bool) []float64 {
+	scale := make([]float64, len(deg))
+	for i, d := range deg {
+		switch {
+		case !normalized:
+			scale[i] = 1
+		case d != 0:
+			scale[i] = 1 / math.Sqrt(d)
+		}
+	}
+	return scale
+}
+
+// isSymmetric returns whether a is a Symmetric or has symmetric elements.
+func isSymmetric(a Matrix) bool {
+	if _, ok := a.(Symmetric); ok {
+		return true
+	}
+	n, c := a.Dims()
+	if n != c {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			if a.At(i, j) != a.At(j, i) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,163 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/matrix"
+)
+
+func TestLaplacian(t *testing.T) {
+	// A path graph 0-1-2 with a weighted edge and a self-loop on node 2,
+	// and an isolated node 3.
+	adj := NewDense(4, 4, []float64{
+		0, 1, 0, 0,
+		1, 0, 2, 0,
+		0, 2, 1, 0,
+		0, 0, 0, 0,
+	})
+	// Degrees are 1, 3, 3 and 0.
+	want := NewDense(4, 4, []float64{
+		1, -1, 0, 0,
+		-1, 3, -2, 0,
+		0, -2, 2, 0,
+		0, 0, 0, 0,
+	})
+	s3 := math.Sqrt(3)
+	wantNorm := NewDense(4, 4, []float64{
+		1, -1 / s3, 0, 0,
+		-1 / s3, 1, -2.0 / 3, 0,
+		0, -2.0 / 3, 1 - 1.0/3, 0,
+		0, 0, 0, 0,
+	})
+
+	for _, test := range []struct {
+		adj        Matrix
+		normalized bool
+		want       *Dense
+	}{
+		{adj: adj, want: want},
+		{adj: adj, normalized: true, want: wantNorm},
+		{adj: NewCSRFrom(adj), want: want},
+		{adj: NewCSRFrom(adj), normalized: true, want: wantNorm},
+		{adj: NewSymDense(4, adj.RawMatrix().Data), want: want},
+	} {
+		got := Laplacian(test.adj, test.normalized)
+		if !EqualApprox(got, test.want, 1e-14) {
+			t.Errorf("unexpected Laplacian for %T normalized=%t:\ngot:\n%v\nwant:\n%v",
+				test.adj, test.normalized, Formatted(got), Formatted(test.want))
+		}
+		switch test.adj.(type) {
+		case *CSR:
+			l, ok := got.(*CSR)
+			if !ok {
+				t.Errorf("unexpected Laplacian type for sparse input: got: %T want: *CSR", got)
+				continue
+			}
+			// The result must be a valid CSR structure with the diagonal stored.
+			NewCSR(l.rows, l.cols, l.indptr, l.ind, l.data)
+			if l.NNZ() != NewCSRFrom(adj).NNZ()+3 {
+				t.Errorf("unexpected number of stored elements: got: %d want: %d", l.NNZ(), NewCSRFrom(adj).NNZ()+3)
+			}
+		default:
+			if _, ok := got.(*SymDense); !ok {
+				t.Errorf("unexpected Laplacian type for undirected graph: got: %T want: *SymDense", got)
+			}
+		}
+	}
+
+	// A directed graph gives the out-degree Laplacian.
+	dir := NewDense(3, 3, []float64{
+		0, 1, 1,
+		0, 0, 1,
+		0, 0, 0,
+	})
+	got := Laplacian(dir, false)
+	wantDir := NewDense(3, 3, []float64{
+		2, -1, -1,
+		0, 1, -1,
+		0, 0, 0,
+	})
+	if _, ok := got.(*Dense); !ok || !Equal(got, wantDir) {
+		t.Errorf("unexpected directed Laplacian %T:\ngot:\n%v\nwant:\n%v", got, Formatted(got), Formatted(wantDir))
+	}
+
+	panicked, message := panics(func() { Laplacian(NewDense(2, 3, nil), false) })
+	if !panicked || message != matrix.ErrSquare.Error() {
+		t.Errorf("expected panic for non-square adjacency matrix")
+	}
+}
+
+func TestSpectralEmbed(t *testing.T) {
+	// Two dense clusters, {0, 1, 2} and {3, 4, 5},
+	// joined by a single weak edge.
+	adj := NewDense(6, 6, []float64{
+		0, 1, 1, 0, 0, 0,
+		1, 0, 1, 0, 0, 0,
+		1, 1, 0, 0.1, 0, 0,
+		0, 0, 0.1, 0, 1, 1,
+		0, 0, 0, 1, 0, 1,
+		0, 0, 0, 1, 1, 0,
+	})
+	for _, a := range []Matrix{adj, NewCSRFrom(adj)} {
+		emb, err := SpectralEmbed(a, 1)
+		if err != nil {
+			t.Errorf("unexpected error for %T: %v", a, err)
+			continue
+		}
+		if r, c := emb.Dims(); r != 6 || c != 1 {
+			t.Errorf("unexpected embedding dimensions: got: %d×%d want: 6×1", r, c)
+			continue
+		}
+		// The clusters must lie on opposite sides of zero.
+		for i := 1; i < 6; i++ {
+			same := (i < 3) == (emb.At(i, 0)*emb.At(0, 0) > 0)
+			if !same {
+				t.Errorf("nodes 0 and %d not separated by cluster: got: %v", i, Col(nil, 0, emb))
+			}
+		}
+	}
+
+	// Without the joining edge the graph is disconnected and has two
+	// zero eigenvalues, leaving four non-zero eigenvalues.
+	disc := DenseCopyOf(adj)
+	disc.Set(2, 3, 0)
+	disc.Set(3, 2, 0)
+	emb, err := SpectralEmbed(disc, 4)
+	if err != nil {
+		t.Fatalf("unexpected error for disconnected graph: %v", err)
+	}
+	var l, lv Dense
+	l.Clone(Laplacian(disc, false))
+	lv.Mul(&l, emb)
+	for j := 0; j < 4; j++ {
+		// Each embedding coordinate is an eigenvector of the
+		// Laplacian with a non-zero eigenvalue.
+		col, lcol := Col(nil, j, emb), Col(nil, j, &lv)
+		lambda := 0.0
+		for i := range col {
+			lambda += col[i] * lcol[i]
+		}
+		if lambda < 1e-8 {
+			t.Errorf("unexpected eigenvalue for coordinate %d: got: %v want: >0", j, lambda)
+		}
+		for i := range col {
+			if math.Abs(lcol[i]-lambda*col[i]) > 1e-10 {
+				t.Errorf("coordinate %d is not an eigenvector", j)
+				break
+			}
+		}
+	}
+	if _, err := SpectralEmbed(disc, 5); err != matrix.ErrShape {
+		t.Errorf("unexpected error for too many dimensions: got: %v want: %v", err, matrix.ErrShape)
+	}
+
+	dir := NewDense(2, 2, []float64{0, 1, 0, 0})
+	if _, err := SpectralEmbed(dir, 1); err != matrix.ErrNotSymmetric {
+		t.Errorf("unexpected error for directed graph: got: %v want: %v", err, matrix.ErrNotSymmetric)
+	}
+}
@@ -143,3 +143,72 @@ func (t *TriDense) set(i, j int, v float64) {
 	}
 	t.mat.Data[i*t.mat.Stride+j] = v
 }
+
+// At returns the element at row i, column j.
+func (b *BandDense) At(i, j int) float64 {
+	return b.at(i, j)
+}
+
+func (b *BandDense) at(i, j int) float64 {
+	if i >= b.mat.Rows || i < 0 {
+		panic(matrix.ErrRowAccess)
+	}
+	if j >= b.mat.Cols || j < 0 {
+		panic(matrix.ErrColAccess)
+	}
+	pj := j + b.mat.KL - i
+	if pj < 0 || b.mat.KL+b.mat.KU+1 <= pj {
+		return 0
+	}
+	return b.mat.Data[i*b.mat.Stride+pj]
+}
+
+// SetBand sets the element at row i, column j to the value v.
+// It panics if the location is outside the band of the matrix.
+func (b *BandDense) SetBand(i, j int, v float64) {
+	b.set(i, j, v)
+}
+
+func (b *BandDense) set(i, j int, v float64) {
+	if i >= b.mat.Rows || i < 0 {
+		panic(matrix.ErrRowAccess)
+	}
+	if j >= b.mat.Cols || j < 0 {
+		panic(matrix.ErrColAccess)
+	}
+	pj := j + b.mat.KL - i
+	if pj < 0 || b.mat.KL+b.mat.KU+1 <= pj {
+		panic(matrix.ErrBandSet)
+	}
+	b.mat.Data[i*b.mat.Stride+pj] = v
+}
+
+// At returns the element at row i, column j.
+func (d *DiagDense) At(i, j int) float64 {
+	return d.at(i, j)
+}
+
+func (d *DiagDense) at(i, j int) float64 {
+	if i >= len(d.data) || i < 0 {
+		panic(matrix.ErrRowAccess)
+	}
+	if j >= len(d.data) || j < 0 {
+		panic(matrix.ErrColAccess)
+	}
+	if i != j {
+		return 0
+	}
+	return d.data[i]
+}
+
+// SetDiag sets the element at row i, column i to the value v.
+func (d *DiagDense) SetDiag(i int, v float64) {
+	d.set(i, v)
+}
+
+func (d *DiagDense) set(i int, v float64) {
+	if i >= len(d.data) || i < 0 {
+		panic(matrix.ErrIndexOutOfRange)
+	}
+	d.data[i] = v
+}
@@ -5,7 +5,12 @@
 package mat64
 
 import (
+	"compress/gzip"
 	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/gonum/matrix"
 )
 
 var (
@@ -16,3 +21,251 @@ var (
 	sizeInt64   = binary.Size(int64(0))
 	sizeFloat64 = binary.Size(float64(0))
 )
+
+// Type tags identifying the matrix type in the compressed format.
+const (
+	compressedDense byte = 'D'
+	compressedSym   byte = 'S'
+	compressedCSR   byte = 'C'
+)
+
+var errCompressedType = errors.New("mat64: unknown compressed matrix type")
+
+// WriteCompressed writes a gzip compressed binary encoding of a to w. The
+// encoding records the type of the matrix, so that ReadCompressed returns a
+// matrix of the same type for *Dense, *SymDense and *CSR values. Matrices of
+// any other type are written as a Dense.
+//
+// The uncompressed stream is a single type tag byte followed by the little-endian
+// encoded matrix:
+//  'D': number of rows and columns (int64), then the elements in row-major order (float64)
+//  'S': size n (int64), then the upper triangle in row-major order (float64)
+//  'C': number of rows, columns and non-zero elements (int64), then the row
+//       pointers and column indices (int64) and the non-zero elements (float64)
+//       of the compressed sparse row representation
+// Compression is effective for matrices with structure or repeated values.
+func WriteCompressed(w io.Writer, a Matrix) error {
+	zw := gzip.NewWriter(w)
+	err := writeTagged(zw, a)
+	if err != nil {
+		zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
+func writeTagged(w io.Writer, a Matrix) error {
+	switch a := a.(type) {
+	case *SymDense:
+		n := a.mat.N
+		err := binary.Write(w, defaultEndian, compressedSym)
+		if err != nil {
+			return err
+		}
+		err = binary.Write(w, defaultEndian, int64(n))
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			err = binary.Write(w, defaultEndian, a.mat.Data[i*a.mat.Stride+i:i*a.mat.Stride+n])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case *CSR:
+		for _, v := range []interface{}{
+			compressedCSR,
+			int64(a.rows), int64(a.cols), int64(len(a.data)),
+			toInt64s(a.indptr), toInt64s(a.ind),
+			a.data,
+		} {
+			err := binary.Write(w, defaultEndian, v)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		m, ok := a.(*Dense)
+		if !ok {
+			m = DenseCopyOf(a)
+		}
+		r, c := m.Dims()
+		for _, v := range []interface{}{compressedDense, int64(r), int64(c)} {
+			err := binary.Write(w, defaultEndian, v)
+			if err != nil {
+				return err
+			}
+		}
+		for i := 0; i < r; i++ {
+			err := binary.Write(w, defaultEndian, m.rowView(i))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// ReadCompressed reads a matrix written by WriteCompressed from r. The returned
+// matrix is a *Dense, *SymDense or *CSR according to the type recorded in the
+// encoding.
+func ReadCompressed(r io.Reader) (Matrix, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var tag byte
+	err = binary.Read(zr, defaultEndian, &tag)
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case compressedDense:
+		dims, err := readDims(zr, 2)
+		if err != nil {
+			return nil, err
+		}
+		r, c := dims[0], dims[1]
+		n, ok := checkedMul(r, c)
+		if !ok {
+			return nil, matrix.ErrShape
+		}
+		data, err := readFloat64s(zr, n)
+		if err != nil {
+			return nil, err
+		}
+		return NewDense(r, c, data), nil
+	case compressedSym:
+		dims, err := readDims(zr, 1)
+		if err != nil {
+			return nil, err
+		}
+		n := dims[0]
+		if _, ok := checkedMul(n, n); !ok {
+			return nil, matrix.ErrShape
+		}
+		// The upper triangle is read before the matrix is allocated
+		// so that a corrupt size cannot cause a large allocation.
+		packed, err := readFloat64s(zr, n*(n+1)/2)
+		if err != nil {
+			return nil, err
+		}
+		s := NewSymDense(n, nil)
+		for i := 0; i < n; i++ {
+			packed = packed[copy(s.mat.Data[i*s.mat.Stride+i:i*s.mat.Stride+n], packed):]
+		}
+		return s, nil
+	case compressedCSR:
+		dims, err := readDims(zr, 3)
+		if err != nil {
+			return nil, err
+		}
+		rows, cols, nnz := dims[0], dims[1], dims[2]
+		if rows == maxInt {
+			return nil, matrix.ErrShape
+		}
+		indptr, err := readInt64s(zr, rows+1)
+		if err != nil {
+			return nil, err
+		}
+		ind, err := readInt64s(zr, nnz)
+		if err != nil {
+			return nil, err
+		}
+		data, err := readFloat64s(zr, nnz)
+		if err != nil {
+			return nil, err
+		}
+		var m *CSR
+		err = matrix.Maybe(func() { m = NewCSR(rows, cols, fromInt64s(indptr), fromInt64s(ind), data) })
+		if err != nil {
+			return nil, err
+		}
+		return m, nil
+	default:
+		return nil, errCompressedType
+	}
+}
+
+// maxInt is the largest value of an int.
+const maxInt = int(^uint(0) >> 1)
+
+// readDims reads n non-negative int64 dimensions from r.
+func readDims(r io.Reader, n int) ([]int, error) {
+	raw := make([]int64, n)
+	err := binary.Read(r, defaultEndian, raw)
+	if err != nil {
+		return nil, err
+	}
+	dims := make([]int, n)
+	for i, v := range raw {
+		if v < 0 || uint64(v) > uint64(maxInt) {
+			return nil, matrix.ErrShape
+		}
+		dims[i] = int(v)
+	}
+	return dims, nil
+}
+
+// checkedMul returns a*b and whether the product is representable as an int
+// for non-negative a and b.
+func checkedMul(a, b int) (int, bool) {
+	if a != 0 && b > maxInt/a {
+		return 0, false
+	}
+	return a * b, true
+}
+
+// readChunk is the maximum number of elements allocated at a time when
+// reading a slice whose length is taken from an encoding. Reading in
+// bounded chunks means that a corrupt length results in an error when the
+// data runs out rather than in an allocation for data that is not present.
+const readChunk = 1 << 16
+
+// readFloat64s reads n float64 values from r.
+func readFloat64s(r io.Reader, n int) ([]float64, error) {
+	s := make([]float64, 0, min(n, readChunk))
+	for len(s) < n {
+		chunk := make([]float64, min(n-len(s), readChunk))
+		err := binary.Read(r, defaultEndian, chunk)
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, chunk...)
+	}
+	return s, nil
+}
+
+// readInt64s reads n int64 values from r.
+func readInt64s(r io.Reader, n int) ([]int64, error) {
+	s := make([]int64, 0, min(n, readChunk))
+	for len(s) < n {
+		chunk := make([]int64, min(n-len(s), readChunk))
+		err := binary.Read(r, defaultEndian, chunk)
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, chunk...)
+	}
+	return s, nil
+}
+
+func toInt64s(s []int) []int64 {
+	t := make([]int64, len(s))
+	for i, v := range s {
+		t[i] = int64(v)
+	}
+	return t
+}
+
+func fromInt64s(s []int64) []int {
+	t := make([]int, len(s))
+	for i, v := range s {
+		t[i] = int(v)
+	}
+	return t
+}
@@ -4,7 +4,15 @@
 
 package mat64
 
-import "testing"
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/matrix"
+)
 
 func TestDenseRW(t *testing.T) {
 	for i, test := range []*Dense{
@@ -39,3 +47,116 @@ func TestDenseRW(t *testing.T) {
 		}
 	}
 }
+
+func TestCompressedRW(t *testing.T) {
+	csr := NewCSR(3, 4, []int{0, 2, 2, 3}, []int{0, 3, 1}, []float64{1, -2, 3})
+	for i, test := range []struct {
+		a    Matrix
+		want Matrix
+	}{
+		{a: NewDense(0, 0, []float64{})},
+		{a: NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6})},
+		{
+			a:    NewDense(3, 3, []float64{1, 2, 3, 4, 5, 6, 7, 8, 9}).View(1, 0, 2, 2),
+			want: NewDense(2, 2, []float64{4, 5, 7, 8}),
+		},
+		{
+			a:    NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}).T(),
+			want: NewDense(3, 2, []float64{1, 4, 2, 5, 3, 6}),
+		},
+		{a: NewSymDense(3, []float64{1, 2, 3, 0, 4, 5, 0, 0, 6})},
+		{a: csr},
+	} {
+		want := test.want
+		if want == nil {
+			want = test.a
+		}
+		var buf bytes.Buffer
+		err := WriteCompressed(&buf, test.a)
+		if err != nil {
+			t.Errorf("unexpected error writing test %d: %v", i, err)
+			continue
+		}
+		got, err := ReadCompressed(&buf)
+		if err != nil {
+			t.Errorf("unexpected error reading test %d: %v", i, err)
+			continue
+		}
+		if reflect.TypeOf(got) != reflect.TypeOf(want) {
+			t.Errorf("unexpected type for test %d: got: %T want: %T", i, got, want)
+		}
+		if !Equal(got, want) {
+			t.Errorf("unexpected round trip for test %d:\ngot:\n% v\nwant:\n% v", i, Formatted(got), Formatted(want))
+		}
+	}
+
+	// A constant matrix compresses well.
+	const n = 100
+	c := NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			c.Set(i, j, 3.5)
+		}
+	}
+	var buf bytes.Buffer
+	err := WriteCompressed(&buf, c)
+	if err != nil {
+		t.Fatalf("unexpected error writing constant matrix: %v", err)
+	}
+	raw := n*n*sizeFloat64 + 2*sizeInt64
+	if buf.Len() > raw/50 {
+		t.Errorf("constant matrix compressed poorly: %d bytes from %d", buf.Len(), raw)
+	}
+
+	// Malformed input returns an error.
+	if _, err := ReadCompressed(bytes.NewReader([]byte("not compressed"))); err == nil {
+		t.Error("expected error reading uncompressed data")
+	}
+	buf.Reset()
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte{'X', 0, 0})
+	zw.Close()
+	if _, err := ReadCompressed(&buf); err != errCompressedType {
+		t.Errorf("unexpected error for unknown type: got: %v want: %v", err, errCompressedType)
+	}
+	buf.Reset()
+	WriteCompressed(&buf, csr)
+	data := buf.Bytes()
+	if _, err := ReadCompressed(bytes.NewReader(data[:len(data)/2])); err == nil {
+		t.Error("expected error reading truncated data")
+	}
+
+	// Corrupt headers with dimensions that overflow or that are not
+	// backed by data return an error without allocating the matrix.
+	const big = 1 << 40
+	for _, test := range []struct {
+		tag     byte
+		dims    []int64
+		payload int
+		want    error
+	}{
+		{tag: compressedDense, dims: []int64{big, big}, want: matrix.ErrShape},
+		{tag: compressedDense, dims: []int64{1 << 20, 1 << 20}, payload: 10},
+		{tag: compressedDense, dims: []int64{2, -1}, want: matrix.ErrShape},
+		{tag: compressedSym, dims: []int64{big}, want: matrix.ErrShape},
+		{tag: compressedSym, dims: []int64{1 << 20}, payload: 10},
+		{tag: compressedCSR, dims: []int64{1<<63 - 1, 2, 0}, want: matrix.ErrShape},
+		{tag: compressedCSR, dims: []int64{big, 2, 0}, payload: 10},
+		{tag: compressedCSR, dims: []int64{1, 2, big}, payload: 10},
+	} {
+		buf.Reset()
+		zw := gzip.NewWriter(&buf)
+		zw.Write([]byte{test.tag})
+		binary.Write(zw, defaultEndian, test.dims)
+		binary.Write(zw, defaultEndian, make([]float64, test.payload))
+		zw.Close()
+		m, err := ReadCompressed(&buf)
+		if err == nil || m != nil {
+			t.Errorf("expected error for %c header %v: got: %v", test.tag, test.dims, err)
+			continue
+		}
+		if test.want != nil && err != test.want {
+			t.Errorf("unexpected error for %c header %v: got: %v want: %v", test.tag, test.dims, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"github.com/gonum/blas/blas64"
+	"github.com/gonum/matrix"
+)
+
+// RankOneApprox computes the best rank-one approximation of a in the
+// Frobenius and 2-norms and places the result in the receiver. The
+// approximation is
+//  σ_1 * u_1 * v_1^T
+// where σ_1 is the largest singular value of a and u_1 and v_1 are the
+// corresponding left and right singular vectors.
+//
+// RankOneApprox returns matrix.ErrZeroMatrix if a has no non-zero singular
+// value and matrix.ErrNoConvergence if the singular value decomposition fails.
+// The receiver is not modified if an error is returned.
+func (m *Dense) RankOneApprox(a Matrix) error {
+	var svd SVD
+	if !svd.Factorize(a, matrix.SVDThin) {
+		return matrix.ErrNoConvergence
+	}
+	if len(svd.s) == 0 || svd.s[0] == 0 {
+		return matrix.ErrZeroMatrix
+	}
+	var u, v Dense
+	u.UFromSVD(&svd)
+	v.VFromSVD(&svd)
+	m.Outer(svd.s[0], u.ColView(0), v.ColView(0))
+	return nil
+}
+
+// LowRankApprox computes the best approximation of a with rank at most rank,
+// in the Frobenius and 2-norms, and places the result in the receiver. The
+// approximation is reconstructed from the truncated singular value decomposition
+//  U_k * Σ_k * V_k^T
+// where Σ_k holds the k largest singular values of a and U_k and V_k hold the
+// corresponding left and right singular vectors. If rank is larger than the
+// smaller dimension of a, it is clamped to that dimension and the receiver
+// holds a copy of a to within working precision.
+//
+// LowRankApprox returns matrix.ErrNoConvergence if the singular value
+// decomposition fails, in which case the receiver is not modified.
+// LowRankApprox will panic if rank is negative.
+func (m *Dense) LowRankApprox(a Matrix, rank int) error {
+	if rank < 0 {
+		panic("mat64: negative rank")
+	}
+	var svd SVD
+	if !svd.Factorize(a, matrix.SVDThin) {
+		return matrix.ErrNoConvergence
+	}
+	r, c := a.Dims()
+	k := min(rank, len(svd.s))
+	if k == 0 {
+		m.reuseAs(r, c)
+		for i := 0; i < r; i++ {
+			zero(m.rowView(i))
+		}
+		return nil
+	}
+	var u, v Dense
+	u.UFromSVD(&svd)
+	v.VFromSVD(&svd)
+	us := DenseCopyOf(u.View(0, 0, r, k))
+	for j, s := range svd.s[:k] {
+		blas64.Scal(r, s, blas64.Vector{Inc: us.mat.Stride, Data: us.mat.Data[j:]})
+	}
+	m.Mul(us, v.View(0, 0, c, k).T())
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/matrix"
+)
+
+func TestRankOneApprox(t *testing.T) {
+	// a is rank two with singular values 5 and 2.
+	x1 := NewVector(4, []float64{0.5, 0.5, 0.5, 0.5})
+	x2 := NewVector(4, []float64{0.5, -0.5, 0.5, -0.5})
+	y1 := NewVector(3, []float64{0.6, 0.8, 0})
+	y2 := NewVector(3, []float64{0, 0, 1})
+	var a, second Dense
+	a.Outer(5, x1, y1)
+	second.Outer(2, x2, y2)
+	a.Add(&a, &second)
+
+	for _, input := range []Matrix{&a, a.T()} {
+		var m Dense
+		err := m.RankOneApprox(input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var diff Dense
+		diff.Sub(input, &m)
+		if e := Norm(&diff, 2); math.Abs(e-2) > 1e-12 {
+			t.Errorf("unexpected approximation error: got: %v want: 2", e)
+		}
+	}
+
+	var want Dense
+	want.Outer(5, x1, y1)
+	var m Dense
+	m.RankOneApprox(&a)
+	if !EqualApprox(&m, &want, 1e-12) {
+		t.Errorf("unexpected rank-one approximation: got: %v want: %v", m.mat.Data, want.mat.Data)
+	}
+
+	// Approximating in place must give the same result.
+	a.RankOneApprox(&a)
+	if !EqualApprox(&a, &want, 1e-12) {
+		t.Errorf("unexpected in-place rank-one approximation")
+	}
+
+	m.Reset()
+	err := m.RankOneApprox(NewDense(3, 2, nil))
+	if err != matrix.ErrZeroMatrix {
+		t.Errorf("unexpected error for zero matrix: got: %v want: %v", err, matrix.ErrZeroMatrix)
+	}
+	if !m.isZero() {
+		t.Errorf("receiver modified on error")
+	}
+}
+
+func TestLowRankApprox(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, dims := range []struct{ r, c int }{{6, 4}, {4, 6}, {5, 5}} {
+		data := make([]float64, dims.r*dims.c)
+		for i := range data {
+			data[i] = rnd.NormFloat64()
+		}
+		a := NewDense(dims.r, dims.c, data)
+		var svd SVD
+		if !svd.Factorize(a, matrix.SVDNone) {
+			t.Fatalf("SVD failed")
+		}
+		s := svd.Values(nil)
+		for rank := 0; rank <= len(s)+1; rank++ {
+			var m Dense
+			err := m.LowRankApprox(a, rank)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var discarded float64
+			if rank < len(s) {
+				for _, v := range s[rank:] {
+					discarded += v * v
+				}
+			}
+			want := math.Sqrt(discarded)
+			var diff Dense
+			diff.Sub(a, &m)
+			if got := Norm(&diff, 2); math.Abs(got-want) > 1e-12 {
+				t.Errorf("unexpected error norm for %d×%d rank %d: got: %v want: %v", dims.r, dims.c, rank, got, want)
+			}
+		}
+	}
+
+	// A rank one approximation must agree with RankOneApprox.
+	a := NewDense(3, 3, []float64{4, 1, 2, 1, 3, 0, 2, 0, 5})
+	var got, want Dense
+	got.LowRankApprox(a, 1)
+	want.RankOneApprox(a)
+	if !EqualApprox(&got, &want, 1e-12) {
+		t.Errorf("unexpected rank one result: got: %v want: %v", got.mat.Data, want.mat.Data)
+	}
+}
@@ -26,10 +26,11 @@ type LU struct {
 
 // updateCond updates the stored condition number of the matrix. Norm is the
 // norm of the original matrix. If norm is negative it will be estimated.
-func (lu *LU) updateCond(norm float64) {
+// Scratch memory is taken from ws, which may be nil.
+func (lu *LU) updateCond(norm float64, ws *Workspace) {
 	n := lu.lu.mat.Cols
-	work := make([]float64, 4*n)
-	iwork := make([]int, n)
+	work := ws.floats(4 * n)
+	iwork := ws.ints(n)
 	if norm < 0 {
 		// This is an approximation. By the defintion of a norm, ||AB|| <= ||A|| ||B||.
 		// The condition number is ||A|| || A^-1||, so this will underestimate
@@ -54,6 +55,14 @@ func (lu *LU) updateCond(norm float64) {
 // factors can be extracted from the factorization using the Permutation method
 // on Dense, and the LFrom and UFrom methods on TriDense.
 func (lu *LU) Factorize(a Matrix) {
+	lu.FactorizeInto(a, nil)
+}
+
+// FactorizeInto computes the LU factorization of the square matrix a as
+// Factorize does, taking scratch memory from ws. When the receiver and ws
+// are reused for matrices of the same size, FactorizeInto does not allocate.
+// If ws is nil, scratch memory is allocated.
+func (lu *LU) FactorizeInto(a Matrix, ws *Workspace) {
 	r, c := a.Dims()
 	if r != c {
 		panic(matrix.ErrSquare)
@@ -61,15 +70,19 @@ func (lu *LU) Factorize(a Matrix) {
 	if lu.lu == nil {
 		lu.lu = &Dense{}
 	}
-	lu.lu.Clone(a)
+	if lr, lc := lu.lu.Dims(); lr == r && lc == c {
+		lu.lu.Copy(a)
+	} else {
+		lu.lu.Clone(a)
+	}
 	if cap(lu.pivot) < r {
 		lu.pivot = make([]int, r)
 	}
 	lu.pivot = lu.pivot[:r]
-	work := make([]float64, r)
+	work := ws.floats(r)
 	anorm := lapack64.Lange(matrix.CondNorm, lu.lu.mat, work)
 	lapack64.Getrf(lu.lu.mat, lu.pivot)
-	lu.updateCond(anorm)
+	lu.updateCond(anorm, ws)
 }
 
 // Det returns the determinant of the matrix that has been factorized. In many
@@ -99,6 +112,21 @@ func (lu *LU) LogDet() (det float64, sign float64) {
 	return floats.Sum(logDiag), sign
 }
 
+// Residual returns the Frobenius norm of the residual
+//  ||A * x - b||
+// of a solution x of the system A * x = b, where a is the matrix that was
+// factorized. Residual is intended for checking the quality of a solution
+// found using SolveLU or SolveLUVec. Residual will panic with matrix.ErrShape
+// if the dimensions of a differ from those of the factorized matrix, or if
+// x and b are not compatible with a.
+func (lu *LU) Residual(a, x, b Matrix) float64 {
+	r, c := a.Dims()
+	if fr, fc := lu.lu.Dims(); r != fr || c != fc {
+		panic(matrix.ErrShape)
+	}
+	return residual(a, x, b)
+}
+
 // Pivot returns pivot indices that enable the construction of the permutation
 // matrix P (see Dense.Permutation). If swaps == nil, then new memory will be
 // allocated, otherwise the length of the input must be equal to the size of the
@@ -182,7 +210,7 @@ func (lu *LU) RankOne(orig *LU, alpha float64, x, y *Vector) {
 			lum.Data[j*lum.Stride+i] += gamma * tmp
 		}
 	}
-	lu.updateCond(-1)
+	lu.updateCond(-1, nil)
 }
 
 // LFromLU extracts the lower triangular matrix from an LU factorization.
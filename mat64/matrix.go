@@ -5,6 +5,8 @@
 package mat64
 
 import (
+	"encoding/binary"
+	"hash/fnv"
 	"math"
 
 	"github.com/gonum/blas"
@@ -202,6 +204,14 @@ type RawVectorer interface {
 	RawVector() blas64.Vector
 }
 
+// A NonZeroCounter can return the number of stored (structural) elements it
+// holds without scanning all of its elements, for example from the structure
+// of a sparse representation. Explicitly stored zeros are included in the
+// count, so it is an upper bound on the number of non-zero elements.
+type NonZeroCounter interface {
+	NNZ() int
+}
+
 // TODO(btracey): Consider adding CopyCol/CopyRow if the behavior seems useful.
 // TODO(btracey): Add in fast paths to Row/Col for the other concrete types
 // (TriDense, etc.) as well as relevant interfaces (Vectorer, RawRowViewer, etc.)
@@ -552,6 +562,57 @@ func EqualApprox(a, b Matrix, epsilon float64) bool {
 	return true
 }
 
+// EqualULP returns whether the matrices a and b have the same size and contain
+// elements that are all equal to within ulp units in the last place. Zeros of
+// either sign are equal, and NaN elements are not equal to any value, including
+// NaN. Matrices with non-equal shapes are not equal.
+//
+// Comparing in units in the last place gives a tolerance that scales with the
+// magnitude of the elements, which is often appropriate for testing numerical
+// algorithms.
+func EqualULP(a, b Matrix, ulp uint) bool {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br || ac != bc {
+		return false
+	}
+	for i := 0; i < ar; i++ {
+		for j := 0; j < ac; j++ {
+			if !floats.EqualWithinULP(a.At(i, j), b.At(i, j), ulp) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Hash returns a 64-bit FNV-1a hash of the dimensions of a and the bit
+// patterns of its elements in row-major order. The hash depends only on the
+// dimensions and element values, not on the type or storage of a, and is
+// stable across runs, so it may be used as a key for caching results computed
+// from a matrix. Elements are hashed by their bits, so zeros of different sign
+// and NaNs with different payloads give different hashes.
+//
+// Hash is not a cryptographic hash and must not be used where collisions
+// could be exploited.
+func Hash(a Matrix) uint64 {
+	r, c := a.Dims()
+	h := fnv.New64a()
+	var buf [8]byte
+	write := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		h.Write(buf[:])
+	}
+	write(uint64(r))
+	write(uint64(c))
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			write(math.Float64bits(a.At(i, j)))
+		}
+	}
+	return h.Sum64()
+}
+
 // LogDet returns the log of the determinant and the sign of the determinant
 // for the matrix that has been factorized. Numerical stability in product and
 // division expressions is generally improved by working in log space.
@@ -856,8 +917,87 @@ func Sum(a Matrix) float64 {
 	return sum
 }
 
-// Trace returns the trace of the matrix. Trace will panic if the
-// matrix is not square.
+// MaskedSum returns the sum of the elements of a at the positions where the
+// corresponding element of mask is non-zero. NaN elements of mask are treated
+// as non-zero. MaskedSum will panic with matrix.ErrShape if a and mask do not
+// have the same dimensions.
+func MaskedSum(a, mask Matrix) float64 {
+	sum, _ := maskedSum(a, mask)
+	return sum
+}
+
+// MaskedMean returns the mean of the elements of a at the positions where the
+// corresponding element of mask is non-zero. NaN elements of mask are treated
+// as non-zero. If mask has no non-zero elements, MaskedMean returns NaN.
+// MaskedMean will panic with matrix.ErrShape if a and mask do not have the
+// same dimensions.
+func MaskedMean(a, mask Matrix) float64 {
+	sum, n := maskedSum(a, mask)
+	return sum / float64(n)
+}
+
+// maskedSum returns the sum of the elements of a where mask is non-zero and
+// the number of elements summed.
+func maskedSum(a, mask Matrix) (sum float64, n int) {
+	r, c := a.Dims()
+	if mr, mc := mask.Dims(); mr != r || mc != c {
+		panic(matrix.ErrShape)
+	}
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if mask.At(i, j) != 0 {
+				sum += a.At(i, j)
+				n++
+			}
+		}
+	}
+	return sum, n
+}
+
+// NNZ returns the number of non-zero elements of the matrix. If the
+// untransposed matrix is a NonZeroCounter, its count of stored elements is
+// returned instead, which includes any explicitly stored zeros. Otherwise the
+// elements are scanned. NaN elements are counted as non-zero.
+func NNZ(a Matrix) int {
+	aU, _ := untranspose(a)
+	if nz, ok := aU.(NonZeroCounter); ok {
+		return nz.NNZ()
+	}
+
+	var n int
+	if rma, ok := aU.(RawMatrixer); ok {
+		rm := rma.RawMatrix()
+		for i := 0; i < rm.Rows; i++ {
+			for _, v := range rm.Data[i*rm.Stride : i*rm.Stride+rm.Cols] {
+				if v != 0 {
+					n++
+				}
+			}
+		}
+		return n
+	}
+	r, c := a.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if a.At(i, j) != 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Density returns the fraction of the elements of the matrix that are
+// non-zero, NNZ(a)/(r·c). For a NonZeroCounter this is the fraction of
+// stored elements.
+func Density(a Matrix) float64 {
+	r, c := a.Dims()
+	return float64(NNZ(a)) / float64(r*c)
+}
+
+// Trace returns the trace of the matrix. The diagonal elements of matrices
+// with raw storage are added using pairwise summation. Trace will panic if
+// the matrix is not square.
 func Trace(a Matrix) float64 {
 	r, c := a.Dims()
 	if r != c {
@@ -868,25 +1008,13 @@ func Trace(a Matrix) float64 {
 	switch m := aU.(type) {
 	case RawMatrixer:
 		rm := m.RawMatrix()
-		var t float64
-		for i := 0; i < r; i++ {
-			t += rm.Data[i*rm.Stride+i]
-		}
-		return t
+		return pairwiseSum(rm.Data, r, rm.Stride+1)
 	case RawTriangular:
 		rm := m.RawTriangular()
-		var t float64
-		for i := 0; i < r; i++ {
-			t += rm.Data[i*rm.Stride+i]
-		}
-		return t
+		return pairwiseSum(rm.Data, r, rm.Stride+1)
 	case RawSymmetricer:
 		rm := m.RawSymmetric()
-		var t float64
-		for i := 0; i < r; i++ {
-			t += rm.Data[i*rm.Stride+i]
-		}
-		return t
+		return pairwiseSum(rm.Data, r, rm.Stride+1)
 	default:
 		var t float64
 		for i := 0; i < r; i++ {
@@ -896,6 +1024,89 @@ func Trace(a Matrix) float64 {
 	}
 }
 
+// TraceInverse returns the trace of the inverse of the square matrix a. The
+// diagonal elements of the inverse are found by solving a * x = e_i for each
+// column e_i of the identity using the LU factorization of a, so the inverse is
+// never stored. TraceInverse will panic with matrix.ErrSquare if a is not square.
+//
+// If a is singular or near-singular a Condition error is returned. If a is
+// exactly singular the returned trace is zero. Please see the documentation for
+// Condition for more information.
+func TraceInverse(a Matrix) (float64, error) {
+	r, c := a.Dims()
+	if r != c {
+		panic(matrix.ErrSquare)
+	}
+	var lu LU
+	lu.Factorize(a)
+	if lu.Det() == 0 {
+		return 0, matrix.Condition(math.Inf(1))
+	}
+	e := NewVector(r, nil)
+	x := NewVector(r, nil)
+	var tr float64
+	for i := 0; i < r; i++ {
+		e.SetVec(i, 1)
+		// Solving can only fail with a Condition error,
+		// which is checked below.
+		x.SolveLUVec(&lu, false, e)
+		e.SetVec(i, 0)
+		tr += x.at(i)
+	}
+	if lu.cond > matrix.ConditionTolerance {
+		return tr, matrix.Condition(lu.cond)
+	}
+	return tr, nil
+}
+
+// BlockTrace returns the partial trace of a over blocks of size blockSize.
+// The n×n matrix a is treated as an (n/blockSize)×(n/blockSize) grid of
+// blockSize×blockSize blocks, and element {i, j} of the returned matrix is
+// the trace of block {i, j}
+//  B[i][j] = \sum_k A[i*blockSize+k][j*blockSize+k].
+// For a composite system with A acting on the tensor product of spaces of
+// dimension n/blockSize and blockSize, this is the partial trace over the
+// second space.
+//
+// BlockTrace will panic with matrix.ErrSquare if a is not square, and with
+// matrix.ErrShape if blockSize is not positive or does not divide the
+// dimension of a.
+func BlockTrace(a Matrix, blockSize int) *Dense {
+	r, c := a.Dims()
+	if r != c {
+		panic(matrix.ErrSquare)
+	}
+	if blockSize <= 0 || r%blockSize != 0 {
+		panic(matrix.ErrShape)
+	}
+	n := r / blockSize
+	t := NewDense(n, n, nil)
+	if rm, ok := a.(RawMatrixer); ok {
+		amat := rm.RawMatrix()
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				var v float64
+				off := i*blockSize*amat.Stride + j*blockSize
+				for k := 0; k < blockSize; k++ {
+					v += amat.Data[off+k*(amat.Stride+1)]
+				}
+				t.mat.Data[i*t.mat.Stride+j] = v
+			}
+		}
+		return t
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			var v float64
+			for k := 0; k < blockSize; k++ {
+				v += a.At(i*blockSize+k, j*blockSize+k)
+			}
+			t.mat.Data[i*t.mat.Stride+j] = v
+		}
+	}
+	return t
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -7,6 +7,7 @@ package mat64
 import (
 	"fmt"
 	"math"
+	"math/rand"
 	"testing"
 
 	"github.com/gonum/blas"
@@ -295,6 +296,96 @@ func TestEqual(t *testing.T) {
 	testTwoInputFunc(t, "Equal", f, denseComparison, sameAnswerBool, legalTypesAll, isAnySize2)
 }
 
+func TestEqualULP(t *testing.T) {
+	next := func(x float64, n int) float64 {
+		for i := 0; i < n; i++ {
+			x = math.Nextafter(x, math.Inf(1))
+		}
+		return x
+	}
+	smallest := math.Float64frombits(1)
+	for i, test := range []struct {
+		a, b Matrix
+		ulp  uint
+		want bool
+	}{
+		{NewDense(1, 2, []float64{1, 2}), NewDense(1, 2, []float64{1, 2}), 0, true},
+		{NewDense(1, 2, []float64{1, 2}), NewDense(1, 2, []float64{next(1, 1), 2}), 0, false},
+		{NewDense(1, 2, []float64{1, 2}), NewDense(1, 2, []float64{next(1, 1), 2}), 1, true},
+		{NewDense(1, 2, []float64{1, 2}), NewDense(1, 2, []float64{next(1, 4), 2}), 3, false},
+		{NewDense(1, 2, []float64{1, 2}), NewDense(1, 2, []float64{next(1, 4), 2}), 4, true},
+		{NewDense(1, 1, []float64{1e300}), NewDense(1, 1, []float64{next(1e300, 2)}), 2, true},
+		{NewDense(1, 1, []float64{1e-300}), NewDense(1, 1, []float64{next(1e-300, 2)}), 1, false},
+
+		// Signed zeros are equal, and the distance across zero is
+		// counted from each side.
+		{NewDense(1, 1, []float64{0}), NewDense(1, 1, []float64{math.Copysign(0, -1)}), 0, true},
+		{NewDense(1, 1, []float64{smallest}), NewDense(1, 1, []float64{-smallest}), 1, false},
+		{NewDense(1, 1, []float64{smallest}), NewDense(1, 1, []float64{-smallest}), 2, true},
+
+		// NaN is not equal to anything.
+		{NewDense(1, 1, []float64{math.NaN()}), NewDense(1, 1, []float64{math.NaN()}), 10, false},
+		{NewDense(1, 1, []float64{math.NaN()}), NewDense(1, 1, []float64{1}), 10, false},
+
+		// Shape and structure.
+		{NewDense(1, 2, []float64{1, 2}), NewDense(2, 1, []float64{1, 2}), 0, false},
+		{NewDense(1, 2, []float64{1, 2}), NewDense(2, 1, []float64{1, 2}).T(), 0, true},
+		{NewSymDense(2, []float64{1, 2, 2, 3}), NewDense(2, 2, []float64{1, 2, 2, next(3, 1)}), 1, true},
+	} {
+		if got := EqualULP(test.a, test.b, test.ulp); got != test.want {
+			t.Errorf("unexpected result for test %d: got: %t want: %t", i, got, test.want)
+		}
+		if got := EqualULP(test.b, test.a, test.ulp); got != test.want {
+			t.Errorf("unexpected result for reversed test %d: got: %t want: %t", i, got, test.want)
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	a := NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6})
+	h := Hash(a)
+	// The hash must be stable across runs and versions.
+	const want = 0x37672c7a5055ea4d
+	if h != want {
+		t.Errorf("unexpected hash: got: %#x want: %#x", h, uint64(want))
+	}
+
+	// Equal matrices hash identically, regardless of type or storage.
+	for i, b := range []Matrix{
+		NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}),
+		NewDense(3, 2, []float64{1, 4, 2, 5, 3, 6}).T(),
+		NewDense(3, 4, []float64{
+			0, 0, 0, 0,
+			0, 1, 2, 3,
+			0, 4, 5, 6,
+		}).View(1, 1, 2, 3),
+	} {
+		if Hash(b) != h {
+			t.Errorf("unexpected hash for equal matrix %d", i)
+		}
+	}
+	s := NewSymDense(2, []float64{1, 2, 2, 3})
+	if Hash(s) != Hash(DenseCopyOf(s)) {
+		t.Error("unexpected hash for symmetric matrix")
+	}
+
+	// Changes to elements or shape change the hash.
+	for i, b := range []Matrix{
+		NewDense(2, 3, []float64{1, 2, 3, 4, 5, 7}),
+		NewDense(2, 3, []float64{1, 2, 3, 4, math.Nextafter(5, 6), 6}),
+		NewDense(2, 3, []float64{2, 1, 3, 4, 5, 6}),
+		NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6}),
+		NewDense(1, 6, []float64{1, 2, 3, 4, 5, 6}),
+	} {
+		if Hash(b) == h {
+			t.Errorf("unexpected hash collision for changed matrix %d", i)
+		}
+	}
+	if Hash(NewDense(1, 1, []float64{0})) == Hash(NewDense(1, 1, []float64{math.Copysign(0, -1)})) {
+		t.Error("unexpected hash collision for signed zeros")
+	}
+}
+
 func TestMax(t *testing.T) {
 	// A direct test of Max with *Dense arguments is in TestNewDense.
 	f := func(a Matrix) interface{} {
@@ -405,6 +496,125 @@ func TestSum(t *testing.T) {
 	testOneInputFunc(t, "Sum", f, denseComparison, sameAnswerFloatApprox, isAnyType, isAnySize)
 }
 
+// nnzCounted is a Matrix that reports a stored non-zero count.
+type nnzCounted struct {
+	*Dense
+	nnz int
+}
+
+func (m nnzCounted) NNZ() int { return m.nnz }
+
+func TestMaskedSum(t *testing.T) {
+	for _, test := range []struct {
+		r, c int
+	}{
+		{1, 1},
+		{1, 5},
+		{4, 1},
+		{3, 4},
+		{6, 7},
+	} {
+		a := NewDense(test.r, test.c, nil)
+		mask := NewDense(test.r, test.c, nil)
+		for i := 0; i < test.r; i++ {
+			for j := 0; j < test.c; j++ {
+				a.Set(i, j, rand.NormFloat64())
+				// Use a checkerboard mask.
+				if (i+j)%2 == 0 {
+					mask.Set(i, j, 1)
+				}
+			}
+		}
+
+		var want float64
+		var n int
+		for i := 0; i < test.r; i++ {
+			for j := 0; j < test.c; j++ {
+				if (i+j)%2 == 0 {
+					want += a.At(i, j)
+					n++
+				}
+			}
+		}
+		if got := MaskedSum(a, mask); got != want {
+			t.Errorf("unexpected masked sum for %d×%d: got: %v want: %v", test.r, test.c, got, want)
+		}
+		if got := MaskedMean(a, mask); got != want/float64(n) {
+			t.Errorf("unexpected masked mean for %d×%d: got: %v want: %v", test.r, test.c, got, want/float64(n))
+		}
+
+		// A full mask gives the plain sum.
+		for i := 0; i < test.r; i++ {
+			for j := 0; j < test.c; j++ {
+				mask.Set(i, j, -2)
+			}
+		}
+		if got, want := MaskedSum(a, mask), Sum(a); math.Abs(got-want) > 1e-14 {
+			t.Errorf("unexpected masked sum with full mask for %d×%d: got: %v want: %v", test.r, test.c, got, want)
+		}
+
+		// An empty mask gives zero sum and NaN mean.
+		zero := NewDense(test.r, test.c, nil)
+		if got := MaskedSum(a, zero); got != 0 {
+			t.Errorf("unexpected masked sum with empty mask for %d×%d: got: %v want: 0", test.r, test.c, got)
+		}
+		if got := MaskedMean(a, zero); !math.IsNaN(got) {
+			t.Errorf("unexpected masked mean with empty mask for %d×%d: got: %v want: NaN", test.r, test.c, got)
+		}
+	}
+
+	a := NewDense(2, 3, nil)
+	for _, mask := range []Matrix{NewDense(3, 2, nil), NewDense(2, 2, nil), NewDense(2, 3, nil).T()} {
+		panicked, message := panics(func() { MaskedSum(a, mask) })
+		if !panicked || message != matrix.ErrShape.Error() {
+			t.Errorf("unexpected panic for mismatched mask: got: %q want: %q", message, matrix.ErrShape)
+		}
+		panicked, message = panics(func() { MaskedMean(a, mask) })
+		if !panicked || message != matrix.ErrShape.Error() {
+			t.Errorf("unexpected panic for mismatched mask: got: %q want: %q", message, matrix.ErrShape)
+		}
+	}
+}
+
+func TestNNZ(t *testing.T) {
+	a := NewDense(3, 4, []float64{
+		0, 1, 0, 0,
+		2, 0, 0, -3,
+		0, 0, math.NaN(), 0,
+	})
+	// csr is
+	//  [1 0 0]
+	//  [0 0 5]
+	// with an explicitly stored zero at {0, 2}.
+	csr := NewCSR(2, 3, []int{0, 2, 3}, []int{0, 2, 2}, []float64{1, 0, 5})
+	for _, test := range []struct {
+		a       Matrix
+		nnz     int
+		density float64
+	}{
+		{a: a, nnz: 4, density: 4.0 / 12},
+		{a: a.T(), nnz: 4, density: 4.0 / 12},
+		{a: a.View(1, 0, 2, 2), nnz: 1, density: 1.0 / 4},
+		{a: NewTriDense(3, true, []float64{1, 2, 3, 0, 4, 5, 0, 0, 6}), nnz: 6, density: 6.0 / 9},
+		{a: NewDense(2, 2, nil), nnz: 0, density: 0},
+		// Matrices that know their count are not scanned.
+		{a: nnzCounted{Dense: NewDense(2, 2, nil), nnz: 3}, nnz: 3, density: 3.0 / 4},
+		{a: Transpose{nnzCounted{Dense: NewDense(2, 2, nil), nnz: 3}}, nnz: 3, density: 3.0 / 4},
+		// Sparse matrices count their stored elements, including
+		// explicit zeros, while their dense copies are scanned.
+		{a: csr, nnz: 3, density: 3.0 / 6},
+		{a: csr.T(), nnz: 3, density: 3.0 / 6},
+		{a: DenseCopyOf(csr), nnz: 2, density: 2.0 / 6},
+	} {
+		if n := NNZ(test.a); n != test.nnz {
+			t.Errorf("unexpected NNZ: got: %d want: %d", n, test.nnz)
+		}
+		if d := Density(test.a); d != test.density {
+			t.Errorf("unexpected density: got: %v want: %v", d, test.density)
+		}
+	}
+}
+
 func TestTrace(t *testing.T) {
 	for _, test := range []struct {
 		a     *Dense
@@ -428,3 +638,106 @@ func TestTrace(t *testing.T) {
 	}
 	testOneInputFunc(t, "Trace", f, denseComparison, sameAnswerFloat, isAnyType, isSquare)
 }
+
+func TestTraceInverse(t *testing.T) {
+	for _, test := range []Matrix{
+		NewDense(1, 1, []float64{4}),
+		NewDense(3, 3, []float64{
+			4, 1, 2,
+			1, -3, 0,
+			2, 0, 5,
+		}),
+		NewDense(3, 3, []float64{
+			0, 1, 2,
+			3, 0, 1,
+			1, 2, 0,
+		}).T(),
+		NewSymDense(2, []float64{
+			2, 1,
+			1, 2,
+		}),
+	} {
+		var inv Dense
+		if err := inv.Inverse(test); err != nil {
+			t.Fatalf("unexpected error inverting matrix: %v", err)
+		}
+		want := Trace(&inv)
+		got, err := TraceInverse(test)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if math.Abs(got-want) > 1e-12 {
+			t.Errorf("unexpected trace of inverse: got: %v want: %v", got, want)
+		}
+	}
+
+	singular := NewDense(2, 2, []float64{
+		1, 2,
+		2, 4,
+	})
+	if _, err := TraceInverse(singular); err == nil {
+		t.Error("expected error for singular matrix")
+	} else if _, ok := err.(matrix.Condition); !ok {
+		t.Errorf("unexpected error type for singular matrix: %T", err)
+	}
+
+	if p, _ := panics(func() { TraceInverse(NewDense(2, 3, nil)) }); !p {
+		t.Error("expected panic for non-square matrix")
+	}
+}
+
+func TestBlockTrace(t *testing.T) {
+	a := NewDense(4, 4, []float64{
+		1, 2, 3, 4,
+		5, 6, 7, 8,
+		9, 10, 11, 12,
+		13, 14, 15, 16,
+	})
+	for i, test := range []struct {
+		a         Matrix
+		blockSize int
+		want      *Dense
+	}{
+		{
+			a:         a,
+			blockSize: 2,
+			want:      NewDense(2, 2, []float64{7, 11, 23, 27}),
+		},
+		{
+			a:         a.T(),
+			blockSize: 2,
+			want:      NewDense(2, 2, []float64{7, 23, 11, 27}),
+		},
+		{
+			a:         a,
+			blockSize: 1,
+			want:      a,
+		},
+		{
+			a:         a,
+			blockSize: 4,
+			want:      NewDense(1, 1, []float64{34}),
+		},
+		{
+			a:         a.View(1, 1, 2, 2),
+			blockSize: 1,
+			want:      NewDense(2, 2, []float64{6, 7, 10, 11}),
+		},
+	} {
+		got := BlockTrace(test.a, test.blockSize)
+		if !Equal(got, test.want) {
+			t.Errorf("unexpected result for BlockTrace test %d: got: %v want: %v", i, got.mat.Data, test.want.mat.Data)
+		}
+	}
+
+	for _, blockSize := range []int{0, 3} {
+		panicked, message := panics(func() { BlockTrace(a, blockSize) })
+		if !panicked || message != matrix.ErrShape.Error() {
+			t.Errorf("expected shape panic for block size %d", blockSize)
+		}
+	}
+	panicked, message := panics(func() { BlockTrace(NewDense(2, 4, nil), 2) })
+	if !panicked || message != matrix.ErrSquare.Error() {
+		t.Errorf("expected square panic for non-square matrix")
+	}
+}
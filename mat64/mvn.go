@@ -0,0 +1,91 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/gonum/blas"
+	"github.com/gonum/blas/blas64"
+	"github.com/gonum/matrix"
+)
+
+// Mahalanobis returns the Mahalanobis distance
+//  sqrt((x-μ)^T * Σ^-1 * (x-μ))
+// between x and the mean μ of a distribution with covariance Σ, where
+// covChol is the Cholesky factorization of Σ. Mahalanobis returns
+// matrix.ErrShape if x, mean and the covariance do not have the same
+// dimension, and matrix.ErrNotPositiveDefinite if the factorization of
+// the covariance failed. If the covariance is ill-conditioned, the distance
+// is returned along with a matrix.Condition error.
+func Mahalanobis(x, mean *Vector, covChol *Cholesky) (float64, error) {
+	n := covChol.Size()
+	if x.Len() != n || mean.Len() != n {
+		return 0, matrix.ErrShape
+	}
+	if !covChol.valid {
+		return 0, matrix.ErrNotPositiveDefinite
+	}
+	// With Σ = U^T * U, the squared distance is |U^-T * (x-μ)|^2.
+	var diff Vector
+	diff.SubVec(x, mean)
+	blas64.Trsv(blas.Trans, covChol.chol.mat, diff.mat)
+	d := blas64.Nrm2(n, diff.mat)
+	if covChol.cond > matrix.ConditionTolerance {
+		return d, matrix.Condition(covChol.cond)
+	}
+	return d, nil
+}
+
+// MVNLogPDF returns the log of the probability density at x of the
+// multivariate normal distribution with the given mean and the covariance
+// Σ whose Cholesky factorization is covChol,
+//  -0.5 * (d*log(2π) + log|Σ| + m^2)
+// where d is the dimension and m is the Mahalanobis distance of x from the
+// mean. MVNLogPDF returns matrix.ErrShape if x, mean and the covariance do
+// not have the same dimension, and matrix.ErrNotPositiveDefinite if the
+// factorization of the covariance failed. If the covariance is
+// ill-conditioned, the log density is returned along with a matrix.Condition
+// error.
+func MVNLogPDF(x, mean *Vector, covChol *Cholesky) (float64, error) {
+	m, err := Mahalanobis(x, mean, covChol)
+	if _, ok := err.(matrix.Condition); err != nil && !ok {
+		return 0, err
+	}
+	d := float64(covChol.Size())
+	return -0.5 * (d*math.Log(2*math.Pi) + covChol.LogDet() + m*m), err
+}
+
+// SampleMVN places into dst a sample from the multivariate normal distribution
+// with the given mean and the covariance Σ whose Cholesky factorization is
+// covChol. The sample is generated as μ + L*z, where Σ = L * L^T and z is a
+// vector of independent standard normal variates drawn from rng, or from the
+// math/rand package's global source if rng is nil.
+//
+// SampleMVN will panic with matrix.ErrShape if mean and the covariance do not
+// have the same dimension, and with matrix.ErrNotPositiveDefinite if the
+// factorization of the covariance failed.
+func SampleMVN(dst *Vector, mean *Vector, covChol *Cholesky, rng *rand.Rand) {
+	n := covChol.Size()
+	if mean.Len() != n {
+		panic(matrix.ErrShape)
+	}
+	if !covChol.valid {
+		panic(matrix.ErrNotPositiveDefinite)
+	}
+	normal := rand.NormFloat64
+	if rng != nil {
+		normal = rng.NormFloat64
+	}
+
+	z := NewVector(n, nil)
+	for i := range z.mat.Data {
+		z.mat.Data[i] = normal()
+	}
+	// The factor is stored as U = L^T.
+	blas64.Trmv(blas.Trans, covChol.chol.mat, z.mat)
+	dst.AddVec(mean, z)
+}
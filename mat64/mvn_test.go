@@ -0,0 +1,177 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/gonum/matrix"
+)
+
+func TestMahalanobis(t *testing.T) {
+	x := NewVector(3, []float64{1, 2, 3})
+	mean := NewVector(3, []float64{-1, 0.5, 4})
+
+	// With identity covariance the distance is Euclidean.
+	var eye Cholesky
+	if !eye.Factorize(NewSymDense(3, []float64{1, 0, 0, 0, 1, 0, 0, 0, 1})) {
+		t.Fatal("unexpected factorization failure")
+	}
+	got, err := Mahalanobis(x, mean, &eye)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := floats.Distance(x.RawVector().Data, mean.RawVector().Data, 2)
+	if math.Abs(got-want) > 1e-14 {
+		t.Errorf("unexpected distance with identity covariance: got: %v want: %v", got, want)
+	}
+
+	// A general covariance must match the explicit inverse.
+	cov := NewSymDense(3, []float64{
+		4, 1, 0.5,
+		1, 3, 0.2,
+		0.5, 0.2, 2,
+	})
+	var chol Cholesky
+	if !chol.Factorize(cov) {
+		t.Fatal("unexpected factorization failure")
+	}
+	got, err = Mahalanobis(x, mean, &chol)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var inv Dense
+	if err := inv.Inverse(cov); err != nil {
+		t.Fatalf("unexpected error inverting covariance: %v", err)
+	}
+	var diff Vector
+	diff.SubVec(x, mean)
+	want = math.Sqrt(Inner(&diff, &inv, &diff))
+	if math.Abs(got-want) > 1e-14 {
+		t.Errorf("unexpected distance: got: %v want: %v", got, want)
+	}
+
+	if _, err := Mahalanobis(NewVector(2, nil), mean, &chol); err != matrix.ErrShape {
+		t.Errorf("unexpected error for mismatched dimensions: got: %v want: %v", err, matrix.ErrShape)
+	}
+	if _, err := Mahalanobis(x, NewVector(4, nil), &chol); err != matrix.ErrShape {
+		t.Errorf("unexpected error for mismatched dimensions: got: %v want: %v", err, matrix.ErrShape)
+	}
+	var bad Cholesky
+	bad.Factorize(NewSymDense(3, []float64{1, 2, 0, 2, 1, 0, 0, 0, 1}))
+	if _, err := Mahalanobis(x, mean, &bad); err != matrix.ErrNotPositiveDefinite {
+		t.Errorf("unexpected error for failed factorization: got: %v want: %v", err, matrix.ErrNotPositiveDefinite)
+	}
+
+	// An ill-conditioned but positive definite covariance gives the
+	// distance along with a Condition error.
+	var ill Cholesky
+	if !ill.Factorize(NewSymDense(2, []float64{1, 0, 0, 1e-20})) {
+		t.Fatal("unexpected factorization failure for ill-conditioned covariance")
+	}
+	got, err = Mahalanobis(NewVector(2, []float64{3, 1e-10}), NewVector(2, nil), &ill)
+	if _, ok := err.(matrix.Condition); !ok {
+		t.Errorf("unexpected error for ill-conditioned covariance: got: %v want: matrix.Condition", err)
+	}
+	if want := math.Sqrt(10); math.Abs(got-want) > 1e-14 {
+		t.Errorf("unexpected distance for ill-conditioned covariance: got: %v want: %v", got, want)
+	}
+}
+
+func TestMVNLogPDF(t *testing.T) {
+	// A one-dimensional distribution is the univariate normal.
+	for _, test := range []struct {
+		x, mu, sigma float64
+	}{
+		{x: 0, mu: 0, sigma: 1},
+		{x: 1.5, mu: -0.5, sigma: 2},
+		{x: -3, mu: 4, sigma: 0.25},
+	} {
+		var chol Cholesky
+		if !chol.Factorize(NewSymDense(1, []float64{test.sigma * test.sigma})) {
+			t.Fatal("unexpected factorization failure")
+		}
+		got, err := MVNLogPDF(NewVector(1, []float64{test.x}), NewVector(1, []float64{test.mu}), &chol)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		z := (test.x - test.mu) / test.sigma
+		want := math.Log(math.Exp(-z*z/2) / (test.sigma * math.Sqrt(2*math.Pi)))
+		if math.Abs(got-want) > 1e-12 {
+			t.Errorf("unexpected log density for %+v: got: %v want: %v", test, got, want)
+		}
+	}
+
+	// Independent components give the sum of univariate log densities.
+	var chol Cholesky
+	if !chol.Factorize(NewSymDense(2, []float64{4, 0, 0, 9})) {
+		t.Fatal("unexpected factorization failure")
+	}
+	got, err := MVNLogPDF(NewVector(2, []float64{1, -1}), NewVector(2, []float64{0, 2}), &chol)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logNorm := func(x, mu, sigma float64) float64 {
+		z := (x - mu) / sigma
+		return -z*z/2 - math.Log(sigma*math.Sqrt(2*math.Pi))
+	}
+	if want := logNorm(1, 0, 2) + logNorm(-1, 2, 3); math.Abs(got-want) > 1e-12 {
+		t.Errorf("unexpected log density for independent components: got: %v want: %v", got, want)
+	}
+
+	var bad Cholesky
+	bad.Factorize(NewSymDense(2, []float64{1, 2, 2, 1}))
+	if _, err := MVNLogPDF(NewVector(2, nil), NewVector(2, nil), &bad); err != matrix.ErrNotPositiveDefinite {
+		t.Errorf("unexpected error for non positive definite covariance: got: %v want: %v", err, matrix.ErrNotPositiveDefinite)
+	}
+}
+
+func TestSampleMVN(t *testing.T) {
+	mean := NewVector(3, []float64{1, -2, 0.5})
+	cov := NewSymDense(3, []float64{
+		4, 1, 0.5,
+		1, 3, -0.6,
+		0.5, -0.6, 2,
+	})
+	var chol Cholesky
+	if !chol.Factorize(cov) {
+		t.Fatal("unexpected factorization failure")
+	}
+
+	const samples = 20000
+	rnd := rand.New(rand.NewSource(1))
+	x := NewDense(samples, 3, nil)
+	for i := 0; i < samples; i++ {
+		SampleMVN(x.RowView(i), mean, &chol, rnd)
+	}
+
+	var sampleMean Vector
+	sampleMean.MeanVec(x, nil)
+	if !EqualApprox(&sampleMean, mean, 0.05) {
+		t.Errorf("unexpected sample mean: got: %v want: %v", sampleMean.RawVector().Data, mean.RawVector().Data)
+	}
+	var centered Dense
+	centered.Apply(func(_, j int, v float64) float64 { return v - sampleMean.At(j, 0) }, x)
+	var sampleCov Dense
+	sampleCov.Mul(centered.T(), &centered)
+	sampleCov.Scale(1/float64(samples-1), &sampleCov)
+	if !EqualApprox(&sampleCov, cov, 0.1) {
+		t.Errorf("unexpected sample covariance:\ngot:\n%v\nwant:\n%v", Formatted(&sampleCov), Formatted(cov))
+	}
+
+	panicked, message := panics(func() { SampleMVN(new(Vector), NewVector(2, nil), &chol, rnd) })
+	if !panicked || message != matrix.ErrShape.Error() {
+		t.Errorf("expected shape panic for mismatched mean")
+	}
+	var bad Cholesky
+	bad.Factorize(NewSymDense(2, []float64{1, 2, 2, 1}))
+	panicked, message = panics(func() { SampleMVN(new(Vector), NewVector(2, nil), &bad, rnd) })
+	if !panicked || message != matrix.ErrNotPositiveDefinite.Error() {
+		t.Errorf("unexpected panic for failed factorization: got: %q want: %q", message, matrix.ErrNotPositiveDefinite)
+	}
+}
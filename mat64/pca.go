@@ -0,0 +1,188 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+
+	"github.com/gonum/blas/blas64"
+	"github.com/gonum/matrix"
+)
+
+// PCA is a type for computing and using the principal component analysis of
+// a data set.
+type PCA struct {
+	// Whiten specifies whether Transform scales the
+	// projected data to unit variance along each component.
+	Whiten bool
+
+	ok bool
+
+	// mean holds the weighted column means of the analysed data.
+	mean []float64
+	// vecs holds the principal directions in its columns.
+	vecs *Dense
+	// vars holds the variances along the principal directions.
+	vars []float64
+}
+
+// PrincipalComponents performs a weighted principal component analysis on the
+// n×d matrix x, where each row of x is an observation of d features. The data
+// are centered by the weighted column means and the principal directions and
+// variances are computed from the singular value decomposition of the centered
+// data. If weights is nil, all observations are weighted equally, otherwise
+// len(weights) must equal n and PrincipalComponents will panic with
+// matrix.ErrShape if it does not.
+//
+// The variances are computed as the weighted sums of squares along each
+// direction divided by the sum of the weights minus one, so that unit weights
+// give the unbiased sample variances.
+//
+// PrincipalComponents returns whether the analysis was successful. The analysis
+// fails if any weight is negative, if the sum of the weights is not greater than
+// one or if the decomposition fails. Routines that require a successful analysis
+// will panic if it failed.
+func (p *PCA) PrincipalComponents(x Matrix, weights []float64) (ok bool) {
+	n, d := x.Dims()
+	if weights != nil && len(weights) != n {
+		panic(matrix.ErrShape)
+	}
+	p.ok = false
+
+	var sumW float64
+	if weights == nil {
+		sumW = float64(n)
+	} else {
+		for _, w := range weights {
+			if w < 0 {
+				return false
+			}
+			sumW += w
+		}
+	}
+	if !(sumW > 1) {
+		return false
+	}
+
+	p.mean = use(p.mean, d)
+	zero(p.mean)
+	c := DenseCopyOf(x)
+	for i := 0; i < n; i++ {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		for j, v := range c.rowView(i) {
+			p.mean[j] += w * v
+		}
+	}
+	for j := range p.mean {
+		p.mean[j] /= sumW
+	}
+	for i := 0; i < n; i++ {
+		row := c.rowView(i)
+		for j := range row {
+			row[j] -= p.mean[j]
+		}
+		if weights != nil {
+			blas64.Scal(d, math.Sqrt(weights[i]), blas64.Vector{Inc: 1, Data: row})
+		}
+	}
+
+	var svd SVD
+	if !svd.Factorize(c, matrix.SVDThin) {
+		return false
+	}
+	if p.vecs == nil {
+		p.vecs = &Dense{}
+	} else {
+		p.vecs.Reset()
+	}
+	p.vecs.VFromSVD(&svd)
+	p.vars = svd.Values(use(p.vars, len(svd.s)))
+	for i, s := range p.vars {
+		p.vars[i] = s * s / (sumW - 1)
+	}
+	p.ok = true
+	return true
+}
+
+// VectorsTo stores the principal directions of the analysis in the columns of
+// dst, ordered by decreasing variance. For an n×d data matrix, dst is
+// d×min(n, d), and VectorsTo will panic with matrix.ErrShape if dst is
+// non-zero and has a different size.
+//
+// VectorsTo will panic if the receiver does not contain a successful analysis.
+func (p *PCA) VectorsTo(dst *Dense) {
+	if !p.ok {
+		panic("pca: no successful analysis")
+	}
+	dst.reuseAs(p.vecs.Dims())
+	dst.Copy(p.vecs)
+}
+
+// VarsTo stores the variances along the principal directions of the analysis
+// into dst, ordered to match the columns returned by VectorsTo, and returns the
+// result. If dst is nil, a new slice is allocated, otherwise len(dst) must
+// equal min(n, d) and VarsTo will panic with matrix.ErrSliceLengthMismatch if
+// it does not.
+//
+// VarsTo will panic if the receiver does not contain a successful analysis.
+func (p *PCA) VarsTo(dst []float64) []float64 {
+	if !p.ok {
+		panic("pca: no successful analysis")
+	}
+	if dst == nil {
+		dst = make([]float64, len(p.vars))
+	}
+	if len(dst) != len(p.vars) {
+		panic(matrix.ErrSliceLengthMismatch)
+	}
+	copy(dst, p.vars)
+	return dst
+}
+
+// Transform projects the rows of x onto the first components principal
+// directions of the analysis and stores the result in dst. The data are
+// centered by the means of the analysed data before projection. If p.Whiten
+// is true, each projected component is divided by its standard deviation so
+// that the transformed analysed data have unit variance along each component;
+// components with zero variance give non-finite values.
+//
+// x must have the same number of columns as the analysed data, and components
+// must be positive and must not exceed the number of principal directions
+// computed by the analysis, which is at most the number of features. Transform
+// will panic with matrix.ErrShape if these conditions are not met, or if dst
+// is non-zero and is not r×components, where r is the number of rows of x.
+//
+// Transform will panic if the receiver does not contain a successful analysis.
+func (p *PCA) Transform(dst *Dense, x Matrix, components int) {
+	if !p.ok {
+		panic("pca: no successful analysis")
+	}
+	r, c := x.Dims()
+	if c != len(p.mean) || components < 1 || components > len(p.vars) {
+		panic(matrix.ErrShape)
+	}
+
+	centered := DenseCopyOf(x)
+	for i := 0; i < r; i++ {
+		row := centered.rowView(i)
+		for j := range row {
+			row[j] -= p.mean[j]
+		}
+	}
+	dst.reuseAs(r, components)
+	dst.Mul(centered, p.vecs.View(0, 0, c, components))
+	if !p.Whiten {
+		return
+	}
+	for i := 0; i < r; i++ {
+		row := dst.rowView(i)
+		for j := range row {
+			row[j] /= math.Sqrt(p.vars[j])
+		}
+	}
+}
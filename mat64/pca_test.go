@@ -0,0 +1,131 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/floats"
+)
+
+// pcaTestData returns 2-D data with mean {5, -2} lying along the axis {0.6, 0.8}
+// with a smaller spread along the orthogonal axis {-0.8, 0.6}. The sample
+// variances along the two axes are 20/3 and 4/3.
+func pcaTestData() *Dense {
+	t := []float64{-3, -1, 1, 3}
+	e := []float64{1, -1, -1, 1}
+	x := NewDense(len(t), 2, nil)
+	for i := range t {
+		x.Set(i, 0, 5+0.6*t[i]-0.8*e[i])
+		x.Set(i, 1, -2+0.8*t[i]+0.6*e[i])
+	}
+	return x
+}
+
+func TestPrincipalComponents(t *testing.T) {
+	x := pcaTestData()
+	var p PCA
+	if !p.PrincipalComponents(x, nil) {
+		t.Fatal("unexpected failure")
+	}
+	vars := p.VarsTo(nil)
+	if !floats.EqualApprox(vars, []float64{20.0 / 3, 4.0 / 3}, 1e-12) {
+		t.Errorf("unexpected variances: got: %v want: %v", vars, []float64{20.0 / 3, 4.0 / 3})
+	}
+	var vecs Dense
+	p.VectorsTo(&vecs)
+	for j, axis := range [][]float64{{0.6, 0.8}, {-0.8, 0.6}} {
+		// Principal directions are only defined up to sign.
+		dot := floats.Dot(Col(nil, j, &vecs), axis)
+		if math.Abs(math.Abs(dot)-1) > 1e-12 {
+			t.Errorf("unexpected principal direction %d: got: %v want: ±%v", j, Col(nil, j, &vecs), axis)
+		}
+	}
+	if !floats.EqualApprox(p.mean, []float64{5, -2}, 1e-12) {
+		t.Errorf("unexpected mean: got: %v", p.mean)
+	}
+
+	// Weighting each observation by two must match duplicating each observation.
+	var dup Dense
+	dup.Stack(x, x)
+	var pw, pd PCA
+	pw.PrincipalComponents(x, []float64{2, 2, 2, 2})
+	pd.PrincipalComponents(&dup, nil)
+	if !floats.EqualApprox(pw.VarsTo(nil), pd.VarsTo(nil), 1e-12) {
+		t.Errorf("weighted variances do not match duplicated data: got: %v want: %v", pw.VarsTo(nil), pd.VarsTo(nil))
+	}
+
+	for _, test := range []struct {
+		x       Matrix
+		weights []float64
+	}{
+		{x: NewDense(1, 3, []float64{1, 2, 3})},
+		{x: x, weights: []float64{1, -1, 1, 1}},
+		{x: x, weights: []float64{0.25, 0.25, 0.25, 0.25}},
+	} {
+		if p.PrincipalComponents(test.x, test.weights) {
+			t.Errorf("expected failure for degenerate input with weights %v", test.weights)
+		}
+		if panicked, _ := panics(func() { p.VarsTo(nil) }); !panicked {
+			t.Errorf("expected panic after failed analysis")
+		}
+	}
+}
+
+func TestPCATransform(t *testing.T) {
+	x := pcaTestData()
+	var p PCA
+	if !p.PrincipalComponents(x, nil) {
+		t.Fatal("unexpected failure")
+	}
+	var vecs Dense
+	p.VectorsTo(&vecs)
+
+	// Projecting onto all components and reconstructing must recover the data.
+	var proj, rec Dense
+	p.Transform(&proj, x, 2)
+	rec.Mul(&proj, vecs.T())
+	r, c := rec.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			rec.Set(i, j, rec.At(i, j)+p.mean[j])
+		}
+	}
+	if !EqualApprox(&rec, x, 1e-12) {
+		t.Errorf("unexpected reconstruction:\ngot:\n%v\nwant:\n%v", Formatted(&rec), Formatted(x))
+	}
+
+	// Projecting onto the first component keeps the leading column.
+	var first Dense
+	p.Transform(&first, x, 1)
+	if !Equal(&first, proj.View(0, 0, r, 1)) {
+		t.Errorf("unexpected single component projection:\ngot:\n%v\nwant:\n%v", Formatted(&first), Formatted(proj.View(0, 0, r, 1)))
+	}
+
+	// Whitened projections of the analysed data have unit variance.
+	p.Whiten = true
+	var white Dense
+	p.Transform(&white, x, 2)
+	for j := 0; j < 2; j++ {
+		col := Col(nil, j, &white)
+		if v := floats.Dot(col, col) / float64(len(col)-1); math.Abs(v-1) > 1e-12 {
+			t.Errorf("unexpected whitened variance for component %d: got: %v want: 1", j, v)
+		}
+	}
+
+	for _, test := range []struct {
+		x          Matrix
+		components int
+	}{
+		{x: x, components: 0},
+		{x: x, components: 3},
+		{x: NewDense(1, 3, nil), components: 1},
+	} {
+		if panicked, _ := panics(func() { p.Transform(&Dense{}, test.x, test.components) }); !panicked {
+			t.Errorf("expected panic for %d components", test.components)
+		}
+	}
+}
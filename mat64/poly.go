@@ -0,0 +1,50 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"sort"
+
+	"github.com/gonum/matrix"
+)
+
+// PolyRoots returns the roots of the degree n polynomial
+//  p(x) = coeffs[0] + coeffs[1]*x + … + coeffs[n]*x^n,
+// whose coefficients are given in increasing order of power. The n roots,
+// repeated according to their multiplicity, are found as the eigenvalues of
+// the companion matrix of p and are returned in order of increasing real
+// part, and then of increasing imaginary part. Complex roots occur in
+// conjugate pairs.
+//
+// PolyRoots returns matrix.ErrZeroLength if the polynomial has degree less
+// than one, and matrix.ErrZeroLeadingCoeff if coeffs[n] is zero.
+func PolyRoots(coeffs []float64) ([]complex128, error) {
+	n := len(coeffs) - 1
+	if n < 1 {
+		return nil, matrix.ErrZeroLength
+	}
+	if coeffs[n] == 0 {
+		return nil, matrix.ErrZeroLeadingCoeff
+	}
+	var e Eigen
+	if !e.Factorize(CompanionMatrix(coeffs), false) {
+		return nil, matrix.ErrNoConvergence
+	}
+	roots := e.Values(nil)
+	sort.Sort(byRealImag(roots))
+	return roots, nil
+}
+
+// byRealImag sorts complex values by real part and then by imaginary part.
+type byRealImag []complex128
+
+func (v byRealImag) Len() int { return len(v) }
+func (v byRealImag) Less(i, j int) bool {
+	if real(v[i]) != real(v[j]) {
+		return real(v[i]) < real(v[j])
+	}
+	return imag(v[i]) < imag(v[j])
+}
+func (v byRealImag) Swap(i, j int) { v[i], v[j] = v[j], v[i] }
@@ -0,0 +1,80 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math/cmplx"
+	"testing"
+
+	"github.com/gonum/matrix"
+)
+
+func TestPolyRoots(t *testing.T) {
+	for i, test := range []struct {
+		coeffs []float64
+		want   []complex128
+	}{
+		{
+			// 2x + 1
+			coeffs: []float64{1, 2},
+			want:   []complex128{-0.5},
+		},
+		{
+			// (x-1)(x-2)(x+3) = x^3 - 7x + 6
+			coeffs: []float64{6, -7, 0, 1},
+			want:   []complex128{-3, 1, 2},
+		},
+		{
+			// 3(x^2 + 1)
+			coeffs: []float64{3, 0, 3},
+			want:   []complex128{-1i, 1i},
+		},
+		{
+			// (x-2)(x^2 - 2x + 5) = x^3 - 4x^2 + 9x - 10
+			coeffs: []float64{-10, 9, -4, 1},
+			want:   []complex128{1 - 2i, 1 + 2i, 2},
+		},
+		{
+			// (x+1)(x^2 + x + 1)(x^2 - 4) = x^5 + 2x^4 - 2x^3 - 7x^2 - 8x - 4
+			coeffs: []float64{-4, -8, -7, -2, 2, 1},
+			want: []complex128{
+				-2,
+				-1,
+				complex(-0.5, -0.8660254037844386),
+				complex(-0.5, 0.8660254037844386),
+				2,
+			},
+		},
+	} {
+		got, err := PolyRoots(test.coeffs)
+		if err != nil {
+			t.Errorf("unexpected error for test %d: %v", i, err)
+			continue
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("unexpected number of roots for test %d: got: %d want: %d", i, len(got), len(test.want))
+			continue
+		}
+		for j, r := range got {
+			if cmplx.Abs(r-test.want[j]) > 1e-10 {
+				t.Errorf("unexpected roots for test %d: got: %v want: %v", i, got, test.want)
+				break
+			}
+		}
+	}
+
+	for _, test := range []struct {
+		coeffs []float64
+		want   error
+	}{
+		{nil, matrix.ErrZeroLength},
+		{[]float64{5}, matrix.ErrZeroLength},
+		{[]float64{1, 1, 0}, matrix.ErrZeroLeadingCoeff},
+	} {
+		if _, err := PolyRoots(test.coeffs); err != test.want {
+			t.Errorf("unexpected error for coefficients %v: got: %v want: %v", test.coeffs, err, test.want)
+		}
+	}
+}
@@ -21,12 +21,12 @@ type QR struct {
 	cond float64
 }
 
-func (qr *QR) updateCond() {
+func (qr *QR) updateCond(ws *Workspace) {
 	// A = QR, where Q is orthonormal. Orthonormal multiplications do not change
 	// the condition number. Thus, ||A|| = ||Q|| ||R|| = ||R||.
 	n := qr.qr.mat.Cols
-	work := make([]float64, 3*n)
-	iwork := make([]int, n)
+	work := ws.floats(3 * n)
+	iwork := ws.ints(n)
 	r := qr.qr.asTriDense(n, blas.NonUnit, blas.Upper)
 	v := lapack64.Trcon(matrix.CondNorm, r.mat, work, iwork)
 	qr.cond = 1 / v
@@ -39,6 +39,14 @@ func (qr *QR) updateCond() {
 // The matrix Q is an orthonormal m×m matrix, and R is an m×n upper triangular matrix.
 // Q and R can be extracted from the QFromQR and RFromQR methods on Dense.
 func (qr *QR) Factorize(a Matrix) {
+	qr.FactorizeInto(a, nil)
+}
+
+// FactorizeInto computes the QR factorization of an m×n matrix a where m >= n
+// as Factorize does, taking scratch memory from ws. When the receiver and ws
+// are reused for matrices of the same size, FactorizeInto does not allocate.
+// If ws is nil, scratch memory is allocated.
+func (qr *QR) FactorizeInto(a Matrix, ws *Workspace) {
 	m, n := a.Dims()
 	if m < n {
 		panic(matrix.ErrShape)
@@ -47,14 +55,33 @@ func (qr *QR) Factorize(a Matrix) {
 	if qr.qr == nil {
 		qr.qr = &Dense{}
 	}
-	qr.qr.Clone(a)
-	work := make([]float64, 1)
-	qr.tau = make([]float64, k)
+	if qm, qn := qr.qr.Dims(); qm == m && qn == n {
+		qr.qr.Copy(a)
+	} else {
+		qr.qr.Clone(a)
+	}
+	qr.tau = use(qr.tau, k)
+	work := ws.floats(1)
 	lapack64.Geqrf(qr.qr.mat, qr.tau, work, -1)
 
-	work = make([]float64, int(work[0]))
+	work = ws.floats(int(work[0]))
 	lapack64.Geqrf(qr.qr.mat, qr.tau, work, len(work))
-	qr.updateCond()
+	qr.updateCond(ws)
+}
+
+// Residual returns the Frobenius norm of the residual
+//  ||A * x - b||
+// of a solution x of the system A * x = b, where a is the matrix that was
+// factorized. When a has more rows than columns, x is a least squares
+// solution and the residual is not zero in general. Residual will panic with
+// matrix.ErrShape if the dimensions of a differ from those of the factorized
+// matrix, or if x and b are not compatible with a.
+func (qr *QR) Residual(a, x, b Matrix) float64 {
+	r, c := a.Dims()
+	if fr, fc := qr.qr.Dims(); r != fr || c != fc {
+		panic(matrix.ErrShape)
+	}
+	return residual(a, x, b)
 }
 
 // TODO(btracey): Add in the "Reduced" forms for extracting the n×n orthogonal
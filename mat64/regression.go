@@ -0,0 +1,238 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+
+	"github.com/gonum/floats"
+	"github.com/gonum/matrix"
+)
+
+// PolyFit returns the coefficients of the polynomial of the given degree that
+// best fits the points {x[i], y[i]} in the least-squares sense. The coefficients
+// are returned in increasing order of power, so the fitted polynomial is
+//  p(x) = c[0] + c[1]*x + … + c[degree]*x^degree.
+//
+// PolyFit solves the least-squares problem for the Vandermonde matrix of x
+// using the QR factorization. If x and y have different lengths, degree is
+// negative, or there are fewer than degree+1 points, PolyFit returns
+// matrix.ErrShape. If the Vandermonde matrix is ill-conditioned, the
+// coefficients are returned along with a Condition error.
+func PolyFit(x, y []float64, degree int) ([]float64, error) {
+	if len(x) != len(y) || degree < 0 || len(x) <= degree {
+		return nil, matrix.ErrShape
+	}
+	var qr QR
+	qr.Factorize(NewVandermonde(x, degree))
+	c := NewVector(degree+1, nil)
+	err := c.SolveQRVec(&qr, false, NewVector(len(y), y))
+	return c.mat.Data, err
+}
+
+// RidgeSolve returns the ridge regression solution x of the regularized
+// least-squares problem
+//  minimize ||A*x - b||_2^2 + λ*||x||_2^2
+// where A is r×c and b is r×k, computed by solving the regularized normal
+// equations
+//  (A^T*A + λ*I) * x = A^T*b
+// using the Cholesky factorization of A^T*A + λ*I. For λ == 0 this is the
+// ordinary least-squares solution, though for ill-conditioned A the QR based
+// solution given by Solve is more accurate.
+//
+// If A and b do not have the same number of rows RidgeSolve returns
+// matrix.ErrShape. If the regularized normal matrix is not positive definite,
+// as when λ == 0 and A is rank deficient, RidgeSolve returns
+// matrix.ErrNotPositiveDefinite. If the regularized normal matrix is
+// ill-conditioned, the solution is returned along with a Condition error.
+// RidgeSolve will panic with matrix.ErrRegularization if λ is negative or NaN.
+func RidgeSolve(a, b Matrix, lambda float64) (*Dense, error) {
+	if !(lambda >= 0) {
+		panic(matrix.ErrRegularization)
+	}
+	r, c := a.Dims()
+	br, _ := b.Dims()
+	if r != br {
+		return nil, matrix.ErrShape
+	}
+
+	ata := NewSymDense(c, nil)
+	ata.SymOuterK(1, a.T())
+	for i := 0; i < c; i++ {
+		ata.SetSym(i, i, ata.at(i, i)+lambda)
+	}
+	var chol Cholesky
+	if !chol.Factorize(ata) {
+		return nil, matrix.ErrNotPositiveDefinite
+	}
+
+	var x Dense
+	x.Mul(a.T(), b)
+	err := x.SolveCholesky(&chol, &x)
+	return &x, err
+}
+
+// Lasso returns the solution x of the L1-regularized least-squares problem
+//  minimize 1/2*||A*x - b||_2^2 + λ*||x||_1
+// where A is r×c and b is an r×1 column, computed by cyclic coordinate
+// descent with soft-thresholding. The L1 penalty drives coefficients of
+// weakly predictive columns to exactly zero, so larger values of λ give
+// sparser solutions; for λ >= max_j |a_j^T*b| the solution is zero.
+//
+// Lasso does not center or scale the data, and does not fit an intercept.
+// Since the penalty is applied equally to all coefficients, the caller should
+// usually standardize the columns of A and center b before calling Lasso.
+// Columns of A that are entirely zero are given a zero coefficient.
+//
+// The iteration stops when no coefficient changes by more than tol in a full
+// cycle over the columns. If this has not happened after maxIter cycles,
+// Lasso returns matrix.ErrNoConvergence. If b is not an r×1 column, Lasso
+// returns matrix.ErrShape. Lasso will panic with matrix.ErrRegularization if λ
+// is negative or NaN.
+func Lasso(a, b Matrix, lambda float64, maxIter int, tol float64) (*Vector, error) {
+	if !(lambda >= 0) {
+		panic(matrix.ErrRegularization)
+	}
+	r, c := a.Dims()
+	br, bc := b.Dims()
+	if br != r || bc != 1 {
+		return nil, matrix.ErrShape
+	}
+
+	// Work with the columns of a as contiguous rows of a^T.
+	at := NewDense(c, r, nil)
+	at.Copy(a.T())
+	norms := make([]float64, c)
+	for j := range norms {
+		col := at.rowView(j)
+		norms[j] = floats.Dot(col, col)
+	}
+
+	// res holds the current residual b - A*x.
+	x := make([]float64, c)
+	res := Col(nil, 0, b)
+	for iter := 0; iter < maxIter; iter++ {
+		var maxDelta float64
+		for j, z := range norms {
+			if z == 0 {
+				continue
+			}
+			col := at.rowView(j)
+			rho := floats.Dot(col, res) + z*x[j]
+			next := softThreshold(rho, lambda) / z
+			delta := next - x[j]
+			if delta == 0 {
+				continue
+			}
+			floats.AddScaled(res, -delta, col)
+			x[j] = next
+			maxDelta = math.Max(maxDelta, math.Abs(delta))
+		}
+		if maxDelta <= tol {
+			return NewVector(c, x), nil
+		}
+	}
+	return nil, matrix.ErrNoConvergence
+}
+
+// softThreshold returns sign(v)*max(|v|-t, 0).
+func softThreshold(v, t float64) float64 {
+	switch {
+	case v > t:
+		return v - t
+	case v < -t:
+		return v + t
+	default:
+		return 0
+	}
+}
+
+// VIF returns the variance inflation factors of the columns of x. The variance
+// inflation factor of column j is
+//  1 / (1 - R_j^2)
+// where R_j^2 is the coefficient of determination of the least-squares
+// regression of column j on an intercept and the remaining columns of x.
+// Large values indicate that column j is close to a linear combination of the
+// other columns. Columns that are exact linear combinations of the other
+// columns to within working precision, including constant columns, have a
+// variance inflation factor of +Inf.
+//
+// The regressions are computed using the singular value decomposition so that
+// collinearity among the remaining columns does not affect the result.
+// VIF will panic with matrix.ErrShape if x does not have more rows than columns.
+func VIF(x Matrix) []float64 {
+	r, c := x.Dims()
+	if r <= c {
+		panic(matrix.ErrShape)
+	}
+	vif := make([]float64, c)
+	d := NewDense(r, c, nil)
+	y := NewVector(r, nil)
+	var (
+		svd      SVD
+		u        Dense
+		uty, fit Vector
+	)
+	for j := range vif {
+		// Regress column j on an intercept and the other columns.
+		var mean float64
+		for i := 0; i < r; i++ {
+			row := d.rowView(i)
+			row[0] = 1
+			k := 1
+			for l := 0; l < c; l++ {
+				if l == j {
+					continue
+				}
+				row[k] = x.At(i, l)
+				k++
+			}
+			v := x.At(i, j)
+			y.setVec(i, v)
+			mean += v
+		}
+		mean /= float64(r)
+
+		if !svd.Factorize(d, matrix.SVDThin) {
+			vif[j] = math.NaN()
+			continue
+		}
+		// The fitted values are the projection of y onto the
+		// left singular vectors spanning the range of d.
+		s := svd.Values(nil)
+		rank := 0
+		for _, v := range s {
+			if v > s[0]*float64(r)*epsilon {
+				rank++
+			}
+		}
+		u.Reset()
+		u.UFromSVD(&svd)
+		ur := u.View(0, 0, r, rank)
+		uty.Reset()
+		uty.MulVec(ur.T(), y)
+		fit.Reset()
+		fit.MulVec(ur, &uty)
+
+		var ssTot, ssRes, ss float64
+		for i := 0; i < r; i++ {
+			v := y.at(i) - mean
+			ssTot += v * v
+			v = y.at(i) - fit.at(i)
+			ssRes += v * v
+			ss += y.at(i) * y.at(i)
+		}
+		// The rounding error in both sums of squares is relative to
+		// the magnitude of y rather than to its spread about the mean,
+		// so a constant column has a residue that must not be mistaken
+		// for a poor fit.
+		if ssTot <= epsilon*ss || ssRes <= epsilon*ss {
+			vif[j] = math.Inf(1)
+			continue
+		}
+		vif[j] = ssTot / ssRes
+	}
+	return vif
+}
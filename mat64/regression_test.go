@@ -0,0 +1,341 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/gonum/matrix"
+)
+
+func TestPolyFit(t *testing.T) {
+	for i, test := range []struct {
+		coef []float64
+		x    []float64
+	}{
+		{
+			coef: []float64{1, -2, 3},
+			x:    []float64{-2, -1, 0, 1, 2, 3},
+		},
+		{
+			coef: []float64{0.5, 0, -4},
+			x:    []float64{0, 0.1, 0.2, 0.3},
+		},
+		{
+			coef: []float64{-3, 2},
+			x:    []float64{1, 5},
+		},
+	} {
+		y := make([]float64, len(test.x))
+		for j, x := range test.x {
+			p := 1.0
+			for _, c := range test.coef {
+				y[j] += c * p
+				p *= x
+			}
+		}
+		got, err := PolyFit(test.x, y, len(test.coef)-1)
+		if err != nil {
+			t.Errorf("unexpected error for test %d: %v", i, err)
+			continue
+		}
+		if !floats.EqualApprox(got, test.coef, 1e-12) {
+			t.Errorf("unexpected coefficients for test %d: got: %v want: %v", i, got, test.coef)
+		}
+	}
+
+	for _, test := range []struct {
+		x, y   []float64
+		degree int
+	}{
+		{x: []float64{1, 2, 3}, y: []float64{1, 2}, degree: 1},
+		{x: []float64{1, 2}, y: []float64{1, 2}, degree: 2},
+		{x: nil, y: nil, degree: 0},
+		{x: []float64{1, 2}, y: []float64{1, 2}, degree: -1},
+		{x: nil, y: nil, degree: -1},
+	} {
+		_, err := PolyFit(test.x, test.y, test.degree)
+		if err != matrix.ErrShape {
+			t.Errorf("unexpected error for x=%v y=%v degree=%d: got: %v want: %v",
+				test.x, test.y, test.degree, err, matrix.ErrShape)
+		}
+	}
+}
+
+func TestVIF(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const n = 50
+
+	// For two columns, both variance inflation factors are 1/(1-ρ^2)
+	// where ρ is the sample correlation of the columns.
+	x := NewDense(n, 2, nil)
+	for i := 0; i < n; i++ {
+		u := rnd.NormFloat64()
+		x.Set(i, 0, u)
+		x.Set(i, 1, 0.8*u+0.6*rnd.NormFloat64())
+	}
+	a, b := Col(nil, 0, x), Col(nil, 1, x)
+	ma, mb := floats.Sum(a)/n, floats.Sum(b)/n
+	var sab, saa, sbb float64
+	for i := range a {
+		da, db := a[i]-ma, b[i]-mb
+		sab += da * db
+		saa += da * da
+		sbb += db * db
+	}
+	rho := sab / math.Sqrt(saa*sbb)
+	want := 1 / (1 - rho*rho)
+	got := VIF(x)
+	for j, v := range got {
+		if math.Abs(v-want) > 1e-10*want {
+			t.Errorf("unexpected VIF for column %d: got: %v want: %v", j, v, want)
+		}
+	}
+
+	// Identical columns must give very large variance inflation factors
+	// while an independent column remains close to one.
+	x = NewDense(n, 3, nil)
+	for i := 0; i < n; i++ {
+		u := rnd.NormFloat64()
+		x.Set(i, 0, u)
+		x.Set(i, 1, rnd.NormFloat64())
+		x.Set(i, 2, u)
+	}
+	got = VIF(x)
+	for _, j := range []int{0, 2} {
+		if got[j] < 1e10 {
+			t.Errorf("expected very large VIF for duplicated column %d: got: %v", j, got[j])
+		}
+	}
+	if got[1] < 1 || got[1] > 2 {
+		t.Errorf("unexpected VIF for independent column: got: %v", got[1])
+	}
+
+	// Constant and exactly collinear columns have infinite variance
+	// inflation factors.
+	for _, test := range []struct {
+		name string
+		col  func(i int, u, v float64) float64
+	}{
+		{name: "constant", col: func(_ int, _, _ float64) float64 { return 3.7 }},
+		{name: "collinear", col: func(_ int, u, v float64) float64 { return 0.3*u - 1.7*v + 2 }},
+	} {
+		x = NewDense(n, 3, nil)
+		for i := 0; i < n; i++ {
+			u, v := rnd.NormFloat64(), rnd.NormFloat64()
+			x.Set(i, 0, u)
+			x.Set(i, 1, v)
+			x.Set(i, 2, test.col(i, u, v))
+		}
+		got = VIF(x)
+		if !math.IsInf(got[2], 1) {
+			t.Errorf("unexpected VIF for %s column: got: %v want: +Inf", test.name, got[2])
+		}
+	}
+
+	panicked, message := panics(func() { VIF(NewDense(3, 3, nil)) })
+	if !panicked || message != matrix.ErrShape.Error() {
+		t.Errorf("expected shape panic for square input")
+	}
+}
+
+func TestRidgeSolve(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		r = 20
+		c = 4
+	)
+	a := NewDense(r, c, nil)
+	b := NewDense(r, 2, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			a.Set(i, j, rnd.NormFloat64())
+		}
+		b.Set(i, 0, rnd.NormFloat64())
+		b.Set(i, 1, rnd.NormFloat64())
+	}
+
+	// With no regularization the result is the least-squares solution.
+	x, err := RidgeSolve(a, b, 0)
+	if err != nil {
+		t.Fatalf("unexpected error for zero lambda: %v", err)
+	}
+	var ls Dense
+	if err := ls.Solve(a, b); err != nil {
+		t.Fatalf("unexpected error from Solve: %v", err)
+	}
+	if !EqualApprox(x, &ls, 1e-12) {
+		t.Errorf("unexpected solution for zero lambda:\ngot:\n%v\nwant:\n%v", Formatted(x), Formatted(&ls))
+	}
+
+	// Increasing regularization shrinks the coefficients.
+	prev := math.Inf(1)
+	for _, lambda := range []float64{0, 0.1, 1, 10, 100, 1e4} {
+		x, err := RidgeSolve(a, b, lambda)
+		if err != nil {
+			t.Errorf("unexpected error for lambda=%v: %v", lambda, err)
+			continue
+		}
+
+		// Check the regularized normal equations hold.
+		var ax, lhs, scaled, rhs Dense
+		ax.Mul(a, x)
+		lhs.Mul(a.T(), &ax)
+		scaled.Scale(lambda, x)
+		lhs.Add(&lhs, &scaled)
+		rhs.Mul(a.T(), b)
+		if !EqualApprox(&lhs, &rhs, 1e-10) {
+			t.Errorf("normal equations not satisfied for lambda=%v", lambda)
+		}
+
+		norm := floats.Norm(x.RawMatrix().Data, 2)
+		if norm >= prev {
+			t.Errorf("coefficient norm did not shrink for lambda=%v: %v >= %v", lambda, norm, prev)
+		}
+		prev = norm
+	}
+
+	// A zero column makes the normal matrix singular unless regularized.
+	z := DenseCopyOf(a)
+	for i := 0; i < r; i++ {
+		z.Set(i, 2, 0)
+	}
+	if _, err := RidgeSolve(z, b, 0); err != matrix.ErrNotPositiveDefinite {
+		t.Errorf("unexpected error for rank deficient matrix: got: %v want: %v", err, matrix.ErrNotPositiveDefinite)
+	}
+	x, err = RidgeSolve(z, b, 1)
+	if err != nil {
+		t.Errorf("unexpected error for regularized rank deficient matrix: %v", err)
+	}
+	if v := x.At(2, 0); v != 0 {
+		t.Errorf("unexpected coefficient for zero column: got: %v want: 0", v)
+	}
+
+	if _, err := RidgeSolve(a, NewDense(r-1, 1, nil), 1); err != matrix.ErrShape {
+		t.Errorf("unexpected error for mismatched rows: got: %v want: %v", err, matrix.ErrShape)
+	}
+	for _, lambda := range []float64{-1, math.NaN()} {
+		panicked, message := panics(func() { RidgeSolve(a, b, lambda) })
+		if !panicked || message != matrix.ErrRegularization.Error() {
+			t.Errorf("unexpected panic for lambda=%v: got: %q want: %q", lambda, message, matrix.ErrRegularization)
+		}
+	}
+}
+
+func TestLasso(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		r = 100
+		c = 10
+	)
+	// Standardized columns and a centered response generated
+	// from a sparse coefficient vector.
+	trueX := []float64{3, 0, 0, -2, 0, 0, 0, 1.5, 0, 0}
+	a := NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			a.Set(i, j, rnd.NormFloat64())
+		}
+	}
+	for j := 0; j < c; j++ {
+		col := Col(nil, j, a)
+		mean := floats.Sum(col) / r
+		floats.AddConst(-mean, col)
+		floats.Scale(1/floats.Norm(col, 2), col)
+		a.SetCol(j, col)
+	}
+	b := NewDense(r, 1, nil)
+	b.Mul(a, NewVector(c, trueX))
+	for i := 0; i < r; i++ {
+		b.Set(i, 0, b.At(i, 0)+0.05*rnd.NormFloat64())
+	}
+
+	// With no penalty the result is the least-squares solution.
+	x, err := Lasso(a, b, 0, 1000, 1e-12)
+	if err != nil {
+		t.Fatalf("unexpected error for zero lambda: %v", err)
+	}
+	var ls Dense
+	ls.Solve(a, b)
+	if !EqualApprox(x, &ls, 1e-8) {
+		t.Errorf("unexpected solution for zero lambda: got: %v want: %v", x.RawVector().Data, Col(nil, 0, &ls))
+	}
+
+	var atb Dense
+	atb.Mul(a.T(), b)
+	lambdaMax := floats.Max(Col(nil, 0, &atb))
+	if m := -floats.Min(Col(nil, 0, &atb)); m > lambdaMax {
+		lambdaMax = m
+	}
+
+	for _, test := range []struct {
+		lambda  float64
+		maxNonZ int
+	}{
+		{lambda: 0.1, maxNonZ: c},
+		{lambda: 0.5, maxNonZ: 3},
+		{lambda: 0.9 * lambdaMax, maxNonZ: 2},
+		{lambda: lambdaMax, maxNonZ: 0},
+		{lambda: 10 * lambdaMax, maxNonZ: 0},
+	} {
+		x, err := Lasso(a, b, test.lambda, 1000, 1e-12)
+		if err != nil {
+			t.Errorf("unexpected error for lambda=%v: %v", test.lambda, err)
+			continue
+		}
+		var nonZ int
+		for j := 0; j < c; j++ {
+			if x.At(j, 0) != 0 {
+				nonZ++
+				if trueX[j] == 0 && test.lambda >= 0.5 {
+					t.Errorf("unexpected non-zero coefficient %d for lambda=%v: %v", j, test.lambda, x.At(j, 0))
+				}
+			}
+		}
+		if nonZ > test.maxNonZ {
+			t.Errorf("too many non-zero coefficients for lambda=%v: got: %d want: <= %d", test.lambda, nonZ, test.maxNonZ)
+		}
+
+		// Check the optimality conditions: |a_j^T*res| <= λ with
+		// equality and matching sign for non-zero coefficients.
+		res := DenseCopyOf(b)
+		var ax Dense
+		ax.Mul(a, x)
+		res.Sub(res, &ax)
+		var g Dense
+		g.Mul(a.T(), res)
+		for j := 0; j < c; j++ {
+			gj, xj := g.At(j, 0), x.At(j, 0)
+			switch {
+			case xj == 0:
+				if math.Abs(gj) > test.lambda+1e-10 {
+					t.Errorf("optimality violated for zero coefficient %d with lambda=%v: |%v| > λ", j, test.lambda, gj)
+				}
+			case math.Abs(gj-math.Copysign(test.lambda, xj)) > 1e-10:
+				t.Errorf("optimality violated for coefficient %d with lambda=%v: got: %v want: %v",
+					j, test.lambda, gj, math.Copysign(test.lambda, xj))
+			}
+		}
+	}
+
+	if _, err := Lasso(a, b, 0.1, 1, 1e-12); err != matrix.ErrNoConvergence {
+		t.Errorf("unexpected error with a single iteration: got: %v want: %v", err, matrix.ErrNoConvergence)
+	}
+	if _, err := Lasso(a, NewDense(r, 2, nil), 0.1, 100, 1e-12); err != matrix.ErrShape {
+		t.Errorf("unexpected error for multiple column b: got: %v want: %v", err, matrix.ErrShape)
+	}
+	if _, err := Lasso(a, NewDense(r-1, 1, nil), 0.1, 100, 1e-12); err != matrix.ErrShape {
+		t.Errorf("unexpected error for mismatched rows: got: %v want: %v", err, matrix.ErrShape)
+	}
+	for _, lambda := range []float64{-1, math.NaN()} {
+		panicked, message := panics(func() { Lasso(a, b, lambda, 100, 1e-12) })
+		if !panicked || message != matrix.ErrRegularization.Error() {
+			t.Errorf("unexpected panic for lambda=%v: got: %q want: %q", lambda, message, matrix.ErrRegularization)
+		}
+	}
+}
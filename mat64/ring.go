@@ -0,0 +1,86 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import "github.com/gonum/matrix"
+
+var (
+	ringDense *RingDense
+
+	_ Matrix = ringDense
+)
+
+// RingDense is a sliding window over a sequence of rows. It holds at most a
+// fixed number of the most recently pushed rows, and presents them as a Matrix
+// in chronological order, so row 0 is the oldest row held and the last row is
+// the most recently pushed. This is useful for online models that need the
+// last n observations of a time series as a matrix.
+type RingDense struct {
+	mat Dense
+
+	// head is the storage row of the oldest row held,
+	// and len is the number of rows held.
+	head, len int
+}
+
+// NewRingDense returns a new RingDense that holds at most capacity rows of
+// c columns. The returned window is initially empty. NewRingDense will panic
+// with matrix.ErrZeroLength if capacity or c is not positive.
+func NewRingDense(capacity, c int) *RingDense {
+	if capacity <= 0 || c <= 0 {
+		panic(matrix.ErrZeroLength)
+	}
+	return &RingDense{mat: *NewDense(capacity, c, nil)}
+}
+
+// Dims returns the number of rows currently held and the number of columns.
+func (r *RingDense) Dims() (int, int) {
+	return r.len, r.mat.mat.Cols
+}
+
+// Cap returns the maximum number of rows held by the receiver.
+func (r *RingDense) Cap() int {
+	return r.mat.mat.Rows
+}
+
+// At returns the element at row i and column j of the window, where row 0 is
+// the oldest row held.
+func (r *RingDense) At(i, j int) float64 {
+	if i < 0 || i >= r.len {
+		panic(matrix.ErrRowAccess)
+	}
+	if j < 0 || j >= r.mat.mat.Cols {
+		panic(matrix.ErrColAccess)
+	}
+	return r.mat.at((r.head+i)%r.mat.mat.Rows, j)
+}
+
+// T performs an implicit transpose by returning the receiver inside a Transpose.
+func (r *RingDense) T() Matrix {
+	return Transpose{r}
+}
+
+// Push appends a copy of row to the window. If the window is full, the oldest
+// row is overwritten. Push will panic with matrix.ErrShape if the length of row
+// does not match the number of columns of the receiver.
+func (r *RingDense) Push(row *Vector) {
+	if row.Len() != r.mat.mat.Cols {
+		panic(matrix.ErrShape)
+	}
+	capacity := r.mat.mat.Rows
+	i := (r.head + r.len) % capacity
+	if r.len == capacity {
+		r.head = (r.head + 1) % capacity
+	} else {
+		r.len++
+	}
+	Col(r.mat.rowView(i), 0, row)
+}
+
+// Reset empties the window without changing its capacity.
+func (r *RingDense) Reset() {
+	r.head = 0
+	r.len = 0
+}
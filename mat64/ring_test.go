@@ -0,0 +1,72 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"testing"
+
+	"github.com/gonum/matrix"
+)
+
+func TestRingDense(t *testing.T) {
+	const (
+		capacity = 3
+		cols     = 2
+	)
+	r := NewRingDense(capacity, cols)
+	if rows, c := r.Dims(); rows != 0 || c != cols {
+		t.Errorf("unexpected dimensions of empty window: got: %d×%d want: 0×%d", rows, c, cols)
+	}
+	if r.Cap() != capacity {
+		t.Errorf("unexpected capacity: got: %d want: %d", r.Cap(), capacity)
+	}
+
+	for n := 1; n <= 7; n++ {
+		row := NewVector(cols, []float64{float64(n), -float64(n)})
+		r.Push(row)
+		// Modifying the pushed row must not change the window.
+		row.SetVec(0, 0)
+
+		held := min(n, capacity)
+		if rows, _ := r.Dims(); rows != held {
+			t.Errorf("unexpected number of rows after %d pushes: got: %d want: %d", n, rows, held)
+		}
+		want := NewDense(held, cols, nil)
+		for i := 0; i < held; i++ {
+			v := float64(n - held + 1 + i)
+			want.SetRow(i, []float64{v, -v})
+		}
+		if !Equal(r, want) {
+			t.Errorf("unexpected window after %d pushes:\ngot:\n% v\nwant:\n% v", n, Formatted(r), Formatted(want))
+		}
+		if !Equal(r.T(), want.T()) {
+			t.Errorf("unexpected transposed window after %d pushes", n)
+		}
+	}
+
+	r.Reset()
+	if rows, _ := r.Dims(); rows != 0 {
+		t.Errorf("unexpected number of rows after reset: got: %d want: 0", rows)
+	}
+	r.Push(NewVector(cols, []float64{8, -8}))
+	if !Equal(r, NewDense(1, cols, []float64{8, -8})) {
+		t.Errorf("unexpected window after reset and push:\n% v", Formatted(r))
+	}
+
+	for _, test := range []struct {
+		fn   func()
+		want error
+	}{
+		{func() { r.Push(NewVector(cols+1, nil)) }, matrix.ErrShape},
+		{func() { r.At(1, 0) }, matrix.ErrRowAccess},
+		{func() { r.At(0, cols) }, matrix.ErrColAccess},
+		{func() { NewRingDense(0, cols) }, matrix.ErrZeroLength},
+	} {
+		p, msg := panics(test.fn)
+		if !p || msg != test.want.Error() {
+			t.Errorf("unexpected panic: got: %q want: %q", msg, test.want)
+		}
+	}
+}
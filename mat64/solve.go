@@ -5,6 +5,8 @@
 package mat64
 
 import (
+	"math"
+
 	"github.com/gonum/blas"
 	"github.com/gonum/blas/blas64"
 	"github.com/gonum/lapack/lapack64"
@@ -121,3 +123,128 @@ func (v *Vector) SolveVec(a Matrix, b *Vector) error {
 	}
 	return m.Solve(a, bm)
 }
+
+// Equilibrate computes row and column scalings intended to equilibrate the
+// matrix a and reduce its condition number, and places the scaled matrix
+// diag(rowScale)·A·diag(colScale) into the receiver. The scalings are computed
+// as in LAPACK's dgeequ: each row scale is the reciprocal of the largest
+// absolute element of its row, and each column scale is the reciprocal of
+// the largest absolute element of its column after row scaling. Rows and
+// columns that are entirely zero are given a scale of one.
+//
+// If x solves the scaled system (R·A·C)·x = R·b, the solution of the original
+// system A·y = b is y = C·x.
+func (m *Dense) Equilibrate(a Matrix) (rowScale, colScale []float64) {
+	r, c := a.Dims()
+	rowScale = make([]float64, r)
+	colScale = make([]float64, c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			rowScale[i] = math.Max(rowScale[i], math.Abs(a.At(i, j)))
+		}
+	}
+	for i, v := range rowScale {
+		rowScale[i] = reciprocalScale(v)
+	}
+	for j := 0; j < c; j++ {
+		for i := 0; i < r; i++ {
+			colScale[j] = math.Max(colScale[j], rowScale[i]*math.Abs(a.At(i, j)))
+		}
+	}
+	for j, v := range colScale {
+		colScale[j] = reciprocalScale(v)
+	}
+
+	m.Apply(func(i, j int, v float64) float64 {
+		return rowScale[i] * v * colScale[j]
+	}, a)
+	return rowScale, colScale
+}
+
+// reciprocalScale returns the scaling 1/v for a row or column with largest
+// absolute element v, or one if v is zero.
+func reciprocalScale(v float64) float64 {
+	if v == 0 {
+		return 1
+	}
+	return 1 / v
+}
+
+// maxRefine is the maximum number of iterative refinement steps
+// performed by SolveExpert.
+const maxRefine = 5
+
+// SolveExpert solves the square system of linear equations A·X = B, storing
+// the solution in the receiver. SolveExpert equilibrates a, as described for
+// Equilibrate, factorizes the equilibrated matrix using an LU decomposition,
+// solves the system and iteratively refines the solution before unscaling it.
+//
+// SolveExpert returns the estimated reciprocal condition number of the
+// equilibrated matrix. If the matrix is singular or near-singular, a Condition
+// error is returned along with the solution. Please see the documentation for
+// Condition for more information.
+//
+// SolveExpert will panic with matrix.ErrSquare if a is not square and with
+// matrix.ErrShape if a and b do not have the same number of rows.
+func (m *Dense) SolveExpert(a, b Matrix) (rcond float64, err error) {
+	n, c := a.Dims()
+	if n != c {
+		panic(matrix.ErrSquare)
+	}
+	br, bc := b.Dims()
+	if br != n {
+		panic(matrix.ErrShape)
+	}
+
+	var eq Dense
+	rowScale, colScale := eq.Equilibrate(a)
+	var lu LU
+	lu.Factorize(&eq)
+	if lu.Det() == 0 {
+		return 0, matrix.Condition(math.Inf(1))
+	}
+	rcond = 1 / lu.cond
+
+	var rb, x Dense
+	rb.Apply(func(i, _ int, v float64) float64 { return rowScale[i] * v }, b)
+	x.SolveLU(&lu, false, &rb)
+
+	// Refine the solution of the equilibrated system until the correction
+	// is negligible or stops decreasing.
+	var res, d Dense
+	last := math.Inf(1)
+	for i := 0; i < maxRefine; i++ {
+		res.Mul(&eq, &x)
+		res.Sub(&rb, &res)
+		d.SolveLU(&lu, false, &res)
+		dNorm := Norm(&d, math.Inf(1))
+		if dNorm > last/2 {
+			break
+		}
+		x.Add(&x, &d)
+		if dNorm <= epsilon*Norm(&x, math.Inf(1)) {
+			break
+		}
+		last = dNorm
+	}
+
+	m.reuseAs(n, bc)
+	m.Apply(func(i, _ int, v float64) float64 { return colScale[i] * v }, &x)
+	if 1/rcond > matrix.ConditionTolerance {
+		return rcond, matrix.Condition(1 / rcond)
+	}
+	return rcond, nil
+}
+
+// residual returns the Frobenius norm of a * x - b.
+func residual(a, x, b Matrix) float64 {
+	ar, _ := a.Dims()
+	_, xc := x.Dims()
+	if br, bc := b.Dims(); br != ar || bc != xc {
+		panic(matrix.ErrShape)
+	}
+	var r Dense
+	r.Mul(a, x)
+	r.Sub(&r, b)
+	return Norm(&r, 2)
+}
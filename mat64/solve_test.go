@@ -5,8 +5,11 @@
 package mat64
 
 import (
+	"math"
 	"math/rand"
 	"testing"
+
+	"github.com/gonum/matrix"
 )
 
 func TestSolve(t *testing.T) {
@@ -294,3 +297,216 @@ func TestSolveVec(t *testing.T) {
 	}
 	testTwoInput(t, "SolveVec", &Vector{}, method, denseComparison, legalTypesNotVecVec, legalSizeSolve, 1e-12)
 }
+
+func TestEquilibrate(t *testing.T) {
+	// A well conditioned matrix with badly scaled rows and columns.
+	b := NewDense(3, 3, []float64{
+		4, 1, 0.5,
+		1, 3, 1,
+		0.5, 1, 2,
+	})
+	rs := []float64{1e6, 1, 1e-5}
+	cs := []float64{1e-3, 1e4, 1}
+	var a Dense
+	a.Apply(func(i, j int, v float64) float64 { return rs[i] * v * cs[j] }, b)
+
+	var eq Dense
+	rowScale, colScale := eq.Equilibrate(&a)
+	for i := 0; i < 3; i++ {
+		var rowMax float64
+		for j := 0; j < 3; j++ {
+			want := rowScale[i] * a.At(i, j) * colScale[j]
+			if eq.At(i, j) != want {
+				t.Errorf("unexpected scaled element (%d, %d): got: %v want: %v", i, j, eq.At(i, j), want)
+			}
+			rowMax = math.Max(rowMax, math.Abs(eq.At(i, j)))
+		}
+		if rowMax > 1+1e-15 {
+			t.Errorf("unexpected largest element in row %d: got: %v want: <=1", i, rowMax)
+		}
+	}
+	for j := 0; j < 3; j++ {
+		var colMax float64
+		for i := 0; i < 3; i++ {
+			colMax = math.Max(colMax, math.Abs(eq.At(i, j)))
+		}
+		if math.Abs(colMax-1) > 1e-15 {
+			t.Errorf("unexpected largest element in column %d: got: %v want: 1", j, colMax)
+		}
+	}
+
+	before, after := Cond(&a, 1), Cond(&eq, 1)
+	if after > before*1e-6 || after > 10 {
+		t.Errorf("condition number not reduced: before: %v after: %v", before, after)
+	}
+
+	// Solving the scaled system and unscaling must solve the original system.
+	// The right-hand side mixes terms of very different magnitude, which
+	// limits the attainable accuracy.
+	want := NewDense(3, 1, []float64{1, -2, 3})
+	var rhs, x Dense
+	rhs.Mul(&a, want)
+	rhs.Apply(func(i, _ int, v float64) float64 { return rowScale[i] * v }, &rhs)
+	if err := x.Solve(&eq, &rhs); err != nil {
+		t.Fatalf("unexpected error solving scaled system: %v", err)
+	}
+	x.Apply(func(i, _ int, v float64) float64 { return colScale[i] * v }, &x)
+	if !EqualApprox(&x, want, 1e-8) {
+		t.Errorf("unexpected unscaled solution:\ngot:\n%v\nwant:\n%v", Formatted(&x), Formatted(want))
+	}
+
+	// Zero rows and columns are left unscaled.
+	var z Dense
+	rowScale, colScale = z.Equilibrate(NewDense(2, 2, []float64{2, 0, 0, 0}))
+	if rowScale[1] != 1 || colScale[1] != 1 {
+		t.Errorf("unexpected scale for zero row or column: got: %v, %v", rowScale, colScale)
+	}
+}
+
+func TestSolveExpert(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 10; trial++ {
+		// A random matrix with badly scaled rows and columns is ill conditioned,
+		// but is well conditioned once equilibrated.
+		const n = 8
+		a := NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				scale := math.Pow(10, float64(4*(i%3)-4)) * math.Pow(10, float64(3*(j%4)-4))
+				a.Set(i, j, rnd.NormFloat64()*scale)
+			}
+		}
+		want := NewDense(n, 2, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < 2; j++ {
+				want.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		var b Dense
+		b.Mul(a, want)
+
+		var plain, expert Dense
+		if err := plain.Solve(a, &b); err == nil {
+			t.Errorf("expected condition error from Solve for trial %d", trial)
+		}
+		rcond, err := expert.SolveExpert(a, &b)
+		if err != nil {
+			t.Errorf("unexpected error from SolveExpert for trial %d: %v", trial, err)
+		}
+		if rcond < 1e-6 || rcond > 1 {
+			t.Errorf("unexpected reciprocal condition number for trial %d: %v", trial, rcond)
+		}
+
+		// The expert solution must have a small backward error
+		// and be at least as accurate as the plain solution.
+		var res Dense
+		res.Mul(a, &expert)
+		res.Sub(&res, &b)
+		backward := Norm(&res, math.Inf(1)) / (Norm(a, math.Inf(1))*Norm(&expert, math.Inf(1)) + Norm(&b, math.Inf(1)))
+		if backward > 1e-15 {
+			t.Errorf("unexpected backward error for trial %d: %v", trial, backward)
+		}
+		var ePlain, eExpert Dense
+		ePlain.Sub(&plain, want)
+		eExpert.Sub(&expert, want)
+		if fp, fe := Norm(&ePlain, math.Inf(1)), Norm(&eExpert, math.Inf(1)); fe > 2*fp {
+			t.Errorf("SolveExpert less accurate than Solve for trial %d: got: %v plain: %v", trial, fe, fp)
+		}
+	}
+
+	// An exactly singular matrix gives an infinite condition number.
+	var x Dense
+	rcond, err := x.SolveExpert(NewDense(2, 2, []float64{1, 2, 2, 4}), NewDense(2, 1, []float64{1, 2}))
+	if _, ok := err.(matrix.Condition); !ok || rcond != 0 {
+		t.Errorf("unexpected result for singular matrix: rcond: %v err: %v", rcond, err)
+	}
+}
+
+func TestResidual(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const n = 10
+	eps := math.Nextafter(1, 2) - 1
+
+	// A well-conditioned symmetric positive definite matrix, and the
+	// notoriously ill-conditioned Hilbert matrix.
+	g := NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			g.Set(i, j, rnd.NormFloat64())
+		}
+	}
+	good := NewSymDense(n, nil)
+	good.SymOuterK(1, g)
+	for i := 0; i < n; i++ {
+		good.SetSym(i, i, good.At(i, i)+float64(n))
+	}
+	hilbert := NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			hilbert.SetSym(i, j, 1/float64(i+j+1))
+		}
+	}
+	b := NewDense(n, 2, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < 2; j++ {
+			b.Set(i, j, rnd.NormFloat64())
+		}
+	}
+	bnorm := Norm(b, 2)
+
+	residuals := func(a *SymDense) (lu, qr, chol float64) {
+		var x Dense
+		var luf LU
+		luf.Factorize(a)
+		x.SolveLU(&luf, false, b)
+		lu = luf.Residual(a, &x, b) / bnorm
+
+		var qrf QR
+		qrf.Factorize(a)
+		x.SolveQR(&qrf, false, b)
+		qr = qrf.Residual(a, &x, b) / bnorm
+
+		var cf Cholesky
+		if !cf.Factorize(a) {
+			t.Fatal("unexpected Cholesky factorization failure")
+		}
+		x.SolveCholesky(&cf, b)
+		chol = cf.Residual(a, &x, b) / bnorm
+		return lu, qr, chol
+	}
+
+	goodLU, goodQR, goodChol := residuals(good)
+	badLU, badQR, badChol := residuals(hilbert)
+	for _, test := range []struct {
+		name      string
+		good, bad float64
+	}{
+		{"LU", goodLU, badLU},
+		{"QR", goodQR, badQR},
+		{"Cholesky", goodChol, badChol},
+	} {
+		if test.good > 100*eps {
+			t.Errorf("unexpected %s residual for well-conditioned solve: got: %v", test.name, test.good)
+		}
+		if test.bad <= test.good {
+			t.Errorf("%s residual for ill-conditioned solve not larger than well-conditioned: got: %v want > %v",
+				test.name, test.bad, test.good)
+		}
+	}
+
+	var lu LU
+	lu.Factorize(good)
+	x := NewDense(n, 2, nil)
+	for _, test := range []struct {
+		a, x, b Matrix
+	}{
+		{NewDense(n+1, n+1, nil), NewDense(n+1, 2, nil), NewDense(n+1, 2, nil)},
+		{good, NewDense(n+1, 2, nil), b},
+		{good, x, NewDense(n, 3, nil)},
+		{good, x, NewDense(n-1, 2, nil)},
+	} {
+		if p, _ := panics(func() { lu.Residual(test.a, test.x, test.b) }); !p {
+			t.Errorf("expected panic for mismatched dimensions")
+		}
+	}
+}
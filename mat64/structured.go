@@ -0,0 +1,168 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import "github.com/gonum/matrix"
+
+// NewToeplitz returns a new Toeplitz matrix with first column col and first
+// row row. The returned matrix is len(col)×len(row) and is constant along
+// each diagonal, with
+//  T[i][j] = col[i-j] for i >= j
+//  T[i][j] = row[j-i] for i < j.
+// NewToeplitz returns matrix.ErrZeroLength if col or row is empty, and
+// matrix.ErrToeplitzCorner if col[0] and row[0] differ.
+func NewToeplitz(col, row []float64) (*Dense, error) {
+	if len(col) == 0 || len(row) == 0 {
+		return nil, matrix.ErrZeroLength
+	}
+	if col[0] != row[0] {
+		return nil, matrix.ErrToeplitzCorner
+	}
+	t := NewDense(len(col), len(row), nil)
+	for i := range col {
+		r := t.rowView(i)
+		for j := range r {
+			if i >= j {
+				r[j] = col[i-j]
+			} else {
+				r[j] = row[j-i]
+			}
+		}
+	}
+	return t, nil
+}
+
+// NewHankel returns a new Hankel matrix with first column col and last row
+// row. The returned matrix is len(col)×len(row) and is constant along each
+// anti-diagonal, with
+//  H[i][j] = col[i+j] for i+j < len(col)
+//  H[i][j] = row[i+j-len(col)+1] otherwise.
+// NewHankel returns matrix.ErrZeroLength if col or row is empty, and
+// matrix.ErrHankelCorner if the last element of col and row[0] differ.
+func NewHankel(col, row []float64) (*Dense, error) {
+	if len(col) == 0 || len(row) == 0 {
+		return nil, matrix.ErrZeroLength
+	}
+	m := len(col)
+	if col[m-1] != row[0] {
+		return nil, matrix.ErrHankelCorner
+	}
+	h := NewDense(m, len(row), nil)
+	for i := range col {
+		r := h.rowView(i)
+		for j := range r {
+			if i+j < m {
+				r[j] = col[i+j]
+			} else {
+				r[j] = row[i+j-m+1]
+			}
+		}
+	}
+	return h, nil
+}
+
+// NewCirculant returns a new n×n circulant matrix with first column c, where
+// n = len(c). Each column of the matrix is the previous column cyclically
+// shifted down by one element, that is
+//  C[i][j] = c[(i-j) mod n].
+// NewCirculant will panic with matrix.ErrZeroLength if c is empty.
+func NewCirculant(c []float64) *Dense {
+	n := len(c)
+	if n == 0 {
+		panic(matrix.ErrZeroLength)
+	}
+	m := NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		r := m.rowView(i)
+		for j := range r {
+			r[j] = c[(i-j+n)%n]
+		}
+	}
+	return m
+}
+
+// NewVandermonde returns a new len(x)×(degree+1) Vandermonde matrix with
+// columns x^0, x^1, …, x^degree, so row i is
+//  [1, x[i], x[i]^2, …, x[i]^degree].
+// Solving V * c = y for c in the least-squares sense gives the coefficients,
+// in increasing order of power, of the polynomial of the given degree that
+// best fits the points {x[i], y[i]}.
+// NewVandermonde will panic with matrix.ErrZeroLength if x is empty, and with
+// matrix.ErrShape if degree is negative.
+func NewVandermonde(x []float64, degree int) *Dense {
+	if len(x) == 0 {
+		panic(matrix.ErrZeroLength)
+	}
+	if degree < 0 {
+		panic(matrix.ErrShape)
+	}
+	v := NewDense(len(x), degree+1, nil)
+	for i, xi := range x {
+		r := v.rowView(i)
+		p := 1.0
+		for j := range r {
+			r[j] = p
+			p *= xi
+		}
+	}
+	return v
+}
+
+// BlockDiag returns a new block diagonal matrix with the given blocks along
+// its diagonal and zeros elsewhere. The blocks need not be square; the
+// returned matrix has as many rows as the sum of the numbers of rows of the
+// blocks, and as many columns as the sum of their numbers of columns.
+// BlockDiag will panic with matrix.ErrZeroLength if no blocks are given.
+func BlockDiag(blocks ...Matrix) *Dense {
+	if len(blocks) == 0 {
+		panic(matrix.ErrZeroLength)
+	}
+	var r, c int
+	for _, b := range blocks {
+		br, bc := b.Dims()
+		r += br
+		c += bc
+	}
+	m := NewDense(r, c, nil)
+	var i, j int
+	for _, b := range blocks {
+		br, bc := b.Dims()
+		m.View(i, j, br, bc).(*Dense).Copy(b)
+		i += br
+		j += bc
+	}
+	return m
+}
+
+// CompanionMatrix returns the n×n companion matrix of the degree n polynomial
+//  p(x) = coeffs[0] + coeffs[1]*x + … + coeffs[n]*x^n,
+// whose coefficients are given in increasing order of power as for
+// NewVandermonde. The polynomial is first normalized to be monic by dividing
+// by coeffs[n], and the returned matrix has ones on its subdiagonal, the
+// negated normalized coefficients coeffs[0:n]/coeffs[n] in its last column,
+// and zeros elsewhere. The eigenvalues of the companion matrix are the roots
+// of p, so they may be found with the Eigen type.
+//
+// CompanionMatrix will panic with matrix.ErrZeroLength if the polynomial has
+// degree less than one, and with matrix.ErrZeroLeadingCoeff if coeffs[n]
+// is zero.
+func CompanionMatrix(coeffs []float64) *Dense {
+	n := len(coeffs) - 1
+	if n < 1 {
+		panic(matrix.ErrZeroLength)
+	}
+	lead := coeffs[n]
+	if lead == 0 {
+		panic(matrix.ErrZeroLeadingCoeff)
+	}
+	m := NewDense(n, n, nil)
+	for i := 1; i < n; i++ {
+		m.set(i, i-1, 1)
+	}
+	for i, c := range coeffs[:n] {
+		m.set(i, n-1, -c/lead)
+	}
+	return m
+}
@@ -0,0 +1,345 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/gonum/matrix"
+)
+
+func TestNewToeplitz(t *testing.T) {
+	for i, test := range []struct {
+		col, row []float64
+		want     *Dense
+	}{
+		{
+			col: []float64{1, 2, 3},
+			row: []float64{1, 4, 5, 6},
+			want: NewDense(3, 4, []float64{
+				1, 4, 5, 6,
+				2, 1, 4, 5,
+				3, 2, 1, 4,
+			}),
+		},
+		{
+			col: []float64{1, 2, 3, 4},
+			row: []float64{1, 5},
+			want: NewDense(4, 2, []float64{
+				1, 5,
+				2, 1,
+				3, 2,
+				4, 3,
+			}),
+		},
+		{
+			col:  []float64{7},
+			row:  []float64{7},
+			want: NewDense(1, 1, []float64{7}),
+		},
+	} {
+		m, err := NewToeplitz(test.col, test.row)
+		if err != nil {
+			t.Errorf("unexpected error for test %d: %v", i, err)
+			continue
+		}
+		if !Equal(m, test.want) {
+			t.Errorf("unexpected result for test %d: got: %v want: %v", i, m.mat.Data, test.want.mat.Data)
+		}
+
+		// Every diagonal must be constant.
+		r, c := m.Dims()
+		for off := -r + 1; off < c; off++ {
+			d := m.Diagonal(off, nil)
+			for _, v := range d {
+				if v != d[0] {
+					t.Errorf("diagonal %d not constant for test %d: %v", off, i, d)
+					break
+				}
+			}
+		}
+	}
+
+	for _, test := range []struct {
+		col, row []float64
+		err      error
+	}{
+		{col: []float64{1, 2}, row: []float64{2, 3}, err: matrix.ErrToeplitzCorner},
+		{col: nil, row: []float64{2, 3}, err: matrix.ErrZeroLength},
+		{col: []float64{1}, row: nil, err: matrix.ErrZeroLength},
+	} {
+		_, err := NewToeplitz(test.col, test.row)
+		if err != test.err {
+			t.Errorf("unexpected error for col=%v row=%v: got: %v want: %v", test.col, test.row, err, test.err)
+		}
+	}
+}
+
+func TestNewHankel(t *testing.T) {
+	for i, test := range []struct {
+		col, row []float64
+		want     *Dense
+	}{
+		{
+			col: []float64{1, 2, 3},
+			row: []float64{3, 4, 5, 6},
+			want: NewDense(3, 4, []float64{
+				1, 2, 3, 4,
+				2, 3, 4, 5,
+				3, 4, 5, 6,
+			}),
+		},
+		{
+			col: []float64{1, 2, 3, 4},
+			row: []float64{4, 5},
+			want: NewDense(4, 2, []float64{
+				1, 2,
+				2, 3,
+				3, 4,
+				4, 5,
+			}),
+		},
+		{
+			col:  []float64{7},
+			row:  []float64{7},
+			want: NewDense(1, 1, []float64{7}),
+		},
+		{
+			col:  []float64{1, 2, 3},
+			row:  []float64{3},
+			want: NewDense(3, 1, []float64{1, 2, 3}),
+		},
+	} {
+		m, err := NewHankel(test.col, test.row)
+		if err != nil {
+			t.Errorf("unexpected error for test %d: %v", i, err)
+			continue
+		}
+		if !Equal(m, test.want) {
+			t.Errorf("unexpected Hankel matrix for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(m), Formatted(test.want))
+		}
+
+		// Every anti-diagonal must be constant.
+		r, c := m.Dims()
+		for k := 0; k < r+c-1; k++ {
+			var v float64
+			first := true
+			for p := max(0, k-c+1); p <= min(k, r-1); p++ {
+				if first {
+					v = m.At(p, k-p)
+					first = false
+				} else if m.At(p, k-p) != v {
+					t.Errorf("anti-diagonal %d not constant for test %d", k, i)
+					break
+				}
+			}
+		}
+	}
+
+	for _, test := range []struct {
+		col, row []float64
+		err      error
+	}{
+		{col: []float64{1, 2}, row: []float64{1, 3}, err: matrix.ErrHankelCorner},
+		{col: nil, row: []float64{2, 3}, err: matrix.ErrZeroLength},
+		{col: []float64{1}, row: nil, err: matrix.ErrZeroLength},
+	} {
+		_, err := NewHankel(test.col, test.row)
+		if err != test.err {
+			t.Errorf("unexpected error for col=%v row=%v: got: %v want: %v", test.col, test.row, err, test.err)
+		}
+	}
+}
+
+func TestNewCirculant(t *testing.T) {
+	c := []float64{1, 2, 3, 4}
+	got := NewCirculant(c)
+	want := NewDense(4, 4, []float64{
+		1, 4, 3, 2,
+		2, 1, 4, 3,
+		3, 2, 1, 4,
+		4, 3, 2, 1,
+	})
+	if !Equal(got, want) {
+		t.Errorf("unexpected circulant: got: %v want: %v", got.mat.Data, want.mat.Data)
+	}
+	toep, err := NewToeplitz(c, []float64{1, 4, 3, 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !Equal(got, toep) {
+		t.Errorf("circulant does not match equivalent Toeplitz matrix")
+	}
+
+	panicked, message := panics(func() { NewCirculant(nil) })
+	if !panicked || message != matrix.ErrZeroLength.Error() {
+		t.Errorf("expected zero length panic")
+	}
+}
+
+func TestNewVandermonde(t *testing.T) {
+	x := []float64{-1, 0, 2, 3}
+	got := NewVandermonde(x, 2)
+	want := NewDense(4, 3, []float64{
+		1, -1, 1,
+		1, 0, 0,
+		1, 2, 4,
+		1, 3, 9,
+	})
+	if !Equal(got, want) {
+		t.Errorf("unexpected Vandermonde matrix: got: %v want: %v", got.mat.Data, want.mat.Data)
+	}
+
+	// Solving against the Vandermonde matrix must recover the coefficients
+	// of a polynomial sampled without noise.
+	coef := []float64{2, -3, 0.5, 0.25}
+	x = []float64{-2, -1, -0.5, 0, 0.5, 1, 1.5, 2, 3}
+	y := make([]float64, len(x))
+	for i, xi := range x {
+		p := 1.0
+		for _, c := range coef {
+			y[i] += c * p
+			p *= xi
+		}
+	}
+	var c Vector
+	err := c.SolveVec(NewVandermonde(x, len(coef)-1), NewVector(len(y), y))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !EqualApprox(&c, NewVector(len(coef), coef), 1e-12) {
+		t.Errorf("unexpected coefficients: got: %v want: %v", c.mat.Data, coef)
+	}
+
+	for _, test := range []struct {
+		x      []float64
+		degree int
+		want   error
+	}{
+		{x: nil, degree: 1, want: matrix.ErrZeroLength},
+		{x: []float64{1, 2}, degree: -1, want: matrix.ErrShape},
+	} {
+		panicked, message := panics(func() { NewVandermonde(test.x, test.degree) })
+		if !panicked || message != test.want.Error() {
+			t.Errorf("unexpected panic for x=%v degree=%d: got: %q want: %q", test.x, test.degree, message, test.want)
+		}
+	}
+}
+
+func TestBlockDiag(t *testing.T) {
+	a := NewDense(2, 2, []float64{
+		1, 2,
+		3, 4,
+	})
+	b := NewDense(1, 3, []float64{5, 6, 7})
+	c := NewSymDense(2, []float64{
+		8, 9,
+		9, 10,
+	})
+	got := BlockDiag(a, b.T(), c)
+	want := NewDense(7, 5, []float64{
+		1, 2, 0, 0, 0,
+		3, 4, 0, 0, 0,
+		0, 0, 5, 0, 0,
+		0, 0, 6, 0, 0,
+		0, 0, 7, 0, 0,
+		0, 0, 0, 8, 9,
+		0, 0, 0, 9, 10,
+	})
+	if !Equal(got, want) {
+		t.Errorf("unexpected block diagonal matrix:\ngot:\n%v\nwant:\n%v", Formatted(got), Formatted(want))
+	}
+
+	// The result is independent of the blocks.
+	a.Set(0, 0, -1)
+	if got.At(0, 0) != 1 {
+		t.Error("block diagonal matrix shares storage with block")
+	}
+
+	if got := BlockDiag(a); !Equal(got, a) {
+		t.Errorf("unexpected single block result:\ngot:\n%v\nwant:\n%v", Formatted(got), Formatted(a))
+	}
+
+	panicked, message := panics(func() { BlockDiag() })
+	if !panicked || message != matrix.ErrZeroLength.Error() {
+		t.Errorf("expected zero length panic")
+	}
+}
+
+func TestCompanionMatrix(t *testing.T) {
+	for i, test := range []struct {
+		coeffs []float64
+		want   *Dense
+		roots  []float64
+	}{
+		{
+			// 2(x-3)
+			coeffs: []float64{-6, 2},
+			want:   NewDense(1, 1, []float64{3}),
+			roots:  []float64{3},
+		},
+		{
+			// (x-1)(x-2)(x+3) = x^3 - 7x + 6
+			coeffs: []float64{6, -7, 0, 1},
+			want: NewDense(3, 3, []float64{
+				0, 0, -6,
+				1, 0, 7,
+				0, 1, 0,
+			}),
+			roots: []float64{-3, 1, 2},
+		},
+		{
+			// -2(x-1)(x-2)(x-3)(x-4) = -2x^4 + 20x^3 - 70x^2 + 100x - 48
+			coeffs: []float64{-48, 100, -70, 20, -2},
+			want: NewDense(4, 4, []float64{
+				0, 0, 0, -24,
+				1, 0, 0, 50,
+				0, 1, 0, -35,
+				0, 0, 1, 10,
+			}),
+			roots: []float64{1, 2, 3, 4},
+		},
+	} {
+		c := CompanionMatrix(test.coeffs)
+		if !Equal(c, test.want) {
+			t.Errorf("unexpected companion matrix for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(c), Formatted(test.want))
+			continue
+		}
+
+		var e Eigen
+		if !e.Factorize(c, false) {
+			t.Errorf("unexpected eigen factorization failure for test %d", i)
+			continue
+		}
+		got := make([]float64, len(test.roots))
+		for j, v := range e.Values(nil) {
+			if math.Abs(imag(v)) > 1e-10 {
+				t.Errorf("unexpected complex eigenvalue for test %d: %v", i, v)
+			}
+			got[j] = real(v)
+		}
+		sort.Float64s(got)
+		if !floats.EqualApprox(got, test.roots, 1e-10) {
+			t.Errorf("unexpected roots for test %d: got: %v want: %v", i, got, test.roots)
+		}
+	}
+
+	for _, test := range []struct {
+		coeffs []float64
+		want   error
+	}{
+		{nil, matrix.ErrZeroLength},
+		{[]float64{1}, matrix.ErrZeroLength},
+		{[]float64{1, 2, 0}, matrix.ErrZeroLeadingCoeff},
+	} {
+		panicked, message := panics(func() { CompanionMatrix(test.coeffs) })
+		if !panicked || message != test.want.Error() {
+			t.Errorf("unexpected panic for coefficients %v: got: %q want: %q", test.coeffs, message, test.want)
+		}
+	}
+}
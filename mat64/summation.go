@@ -0,0 +1,159 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+	"runtime"
+	"sync"
+)
+
+// sumBlockSize is the approximate number of elements in each block of a
+// blocked summation. The blocking depends only on the shape of the matrix,
+// so the result of a summation does not depend on the number of goroutines
+// used to compute it.
+const sumBlockSize = 1 << 14
+
+// SumKahan returns the sum of the elements of the receiver computed using
+// compensated (Kahan–Babuška) summation, which carries the rounding error of
+// each addition forward and is accurate to within a few ulps of the exact sum
+// independent of the number of elements.
+//
+// Large matrices are divided into blocks of rows that are summed concurrently
+// and the block sums are combined by a compensated pairwise tree reduction.
+// The blocks and the order of reduction depend only on the dimensions of the
+// receiver, so the result is reproducible regardless of GOMAXPROCS or
+// scheduling.
+func (m *Dense) SumKahan() float64 {
+	return m.sumKahan(runtime.GOMAXPROCS(0))
+}
+
+// compensated is a sum with an accumulated correction term.
+type compensated struct {
+	sum, c float64
+}
+
+// add adds v to the compensated sum.
+func (s *compensated) add(v float64) {
+	t := s.sum + v
+	if math.IsInf(t, 0) {
+		// The correction is meaningless for an infinite sum,
+		// and computing it would give NaN from Inf-Inf.
+		s.sum = t
+		return
+	}
+	if math.Abs(s.sum) >= math.Abs(v) {
+		s.c += (s.sum - t) + v
+	} else {
+		s.c += (v - t) + s.sum
+	}
+	s.sum = t
+}
+
+// merge returns the compensated sum of s and t.
+func (s compensated) merge(t compensated) compensated {
+	s.add(t.sum)
+	s.c += t.c
+	return s
+}
+
+// sumKahan performs SumKahan using at most workers goroutines.
+func (m *Dense) sumKahan(workers int) float64 {
+	r, c := m.Dims()
+	if r == 0 || c == 0 {
+		return 0
+	}
+	rows := max(1, sumBlockSize/c)
+	blocks := (r + rows - 1) / rows
+	partial := make([]compensated, blocks)
+	sumBlock := func(b int) {
+		var s compensated
+		for i := b * rows; i < min(r, (b+1)*rows); i++ {
+			for _, v := range m.rowView(i) {
+				s.add(v)
+			}
+		}
+		partial[b] = s
+	}
+
+	workers = min(workers, blocks)
+	if workers <= 1 {
+		for b := range partial {
+			sumBlock(b)
+		}
+	} else {
+		jobs := make(chan int)
+		var wg sync.WaitGroup
+		wg.Add(workers)
+		for w := 0; w < workers; w++ {
+			go func() {
+				defer wg.Done()
+				for b := range jobs {
+					sumBlock(b)
+				}
+			}()
+		}
+		for b := range partial {
+			jobs <- b
+		}
+		close(jobs)
+		wg.Wait()
+	}
+
+	// Reduce the block sums pairwise in a fixed order.
+	for n := len(partial); n > 1; n = (n + 1) / 2 {
+		for i := 0; i < n/2; i++ {
+			partial[i] = partial[2*i].merge(partial[2*i+1])
+		}
+		if n%2 == 1 {
+			partial[n/2] = partial[n-1]
+		}
+	}
+	return partial[0].sum + partial[0].c
+}
+
+// pairwiseBlock is the length below which pairwise summation adds elements
+// sequentially. Summing short runs directly keeps pairwise summation as fast
+// as naive summation without significantly increasing its error bound.
+const pairwiseBlock = 128
+
+// SumPairwise returns the sum of the elements of the receiver computed using
+// pairwise (cascade) summation, which recursively sums each half of the
+// elements and adds the results. The rounding error of pairwise summation
+// grows as O(ε log n) for n elements, rather than the O(ε n) of naive
+// left-to-right summation, at no additional cost.
+func (m *Dense) SumPairwise() float64 {
+	r, c := m.Dims()
+	if r == 0 || c == 0 {
+		return 0
+	}
+	if m.mat.Stride == c {
+		return pairwiseSum(m.mat.Data[:r*c], r*c, 1)
+	}
+	return m.pairwiseRows(0, r)
+}
+
+// pairwiseRows returns the pairwise sum of rows lo to hi-1 of the receiver.
+func (m *Dense) pairwiseRows(lo, hi int) float64 {
+	if hi-lo == 1 {
+		return pairwiseSum(m.rowView(lo), m.mat.Cols, 1)
+	}
+	mid := lo + (hi-lo)/2
+	return m.pairwiseRows(lo, mid) + m.pairwiseRows(mid, hi)
+}
+
+// pairwiseSum returns the pairwise sum of the n elements of x separated by
+// inc.
+func pairwiseSum(x []float64, n, inc int) float64 {
+	if n <= pairwiseBlock {
+		var sum float64
+		for i := 0; i < n*inc; i += inc {
+			sum += x[i]
+		}
+		return sum
+	}
+	h := n / 2
+	return pairwiseSum(x, h, inc) + pairwiseSum(x[h*inc:], n-h, inc)
+}
@@ -0,0 +1,179 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// exactSum returns the sum of the elements of a rounded from an exact
+// arbitrary precision sum.
+func exactSum(a *Dense) float64 {
+	r, c := a.Dims()
+	sum := new(big.Float).SetPrec(2048)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			sum.Add(sum, big.NewFloat(a.At(i, j)))
+		}
+	}
+	f, _ := sum.Float64()
+	return f
+}
+
+func TestSumKahan(t *testing.T) {
+	for i, test := range []struct {
+		a    *Dense
+		want float64
+	}{
+		{
+			a:    NewDense(1, 1, []float64{3}),
+			want: 3,
+		},
+		{
+			// Naive summation loses both small elements.
+			a:    NewDense(2, 2, []float64{1, 1e100, 1, -1e100}),
+			want: 2,
+		},
+		{
+			a: NewDense(3, 2, []float64{
+				1e16, 1,
+				-1e16, 1,
+				1, 0.5,
+			}),
+			want: 3.5,
+		},
+		{
+			a:    NewDense(2, 2, []float64{1, math.Inf(1), 2, 3}),
+			want: math.Inf(1),
+		},
+		{
+			a:    NewDense(1, 3, []float64{1e300, math.Inf(-1), -1}),
+			want: math.Inf(-1),
+		},
+		{
+			// Overflow of finite elements.
+			a:    NewDense(1, 3, []float64{math.MaxFloat64, math.MaxFloat64, -1}),
+			want: math.Inf(1),
+		},
+	} {
+		if got := test.a.SumKahan(); got != test.want {
+			t.Errorf("unexpected sum for test %d: got: %v want: %v", i, got, test.want)
+		}
+	}
+	if got := NewDense(1, 2, []float64{math.Inf(1), math.Inf(-1)}).SumKahan(); !math.IsNaN(got) {
+		t.Errorf("unexpected sum of opposite infinities: got: %v want: NaN", got)
+	}
+
+	eps := math.Nextafter(1, 2) - 1
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		r, c int
+	}{
+		{1, 1000},
+		{1000, 1},
+		{300, 200},
+		{1000, 70},
+	} {
+		// Elements spanning many orders of magnitude with both signs
+		// cause large cancellation errors in naive summation.
+		a := NewDense(test.r, test.c, nil)
+		for i := 0; i < test.r; i++ {
+			for j := 0; j < test.c; j++ {
+				a.Set(i, j, rnd.NormFloat64()*math.Pow(10, float64(rnd.Intn(20))))
+			}
+		}
+		want := exactSum(a)
+		got := a.SumKahan()
+		if math.Abs(got-want) > 4*math.Abs(want)*eps {
+			t.Errorf("unexpected sum for %d×%d: got: %v want: %v", test.r, test.c, got, want)
+		}
+		naive := Sum(a)
+		if math.Abs(got-want) > math.Abs(naive-want) {
+			t.Errorf("compensated sum less accurate than naive sum for %d×%d: got: %v naive: %v want: %v",
+				test.r, test.c, got, naive, want)
+		}
+
+		// The result must not depend on the number of workers.
+		for _, workers := range []int{1, 2, 3, 8} {
+			if s := a.sumKahan(workers); math.Float64bits(s) != math.Float64bits(got) {
+				t.Errorf("sum for %d×%d depends on worker count: got: %v with %d workers want: %v",
+					test.r, test.c, s, workers, got)
+			}
+		}
+	}
+}
+
+func BenchmarkSumNaive(b *testing.B) {
+	sumBench(b, func(a *Dense) float64 { return Sum(a) })
+}
+func BenchmarkSumKahanSerial(b *testing.B) {
+	sumBench(b, func(a *Dense) float64 { return a.sumKahan(1) })
+}
+func BenchmarkSumKahanParallel(b *testing.B) {
+	sumBench(b, (*Dense).SumKahan)
+}
+
+func sumBench(b *testing.B, sum func(*Dense) float64) {
+	const n = 1000
+	a := NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			a.Set(i, j, rand.Float64())
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sum(a)
+	}
+}
+
+func TestSumPairwise(t *testing.T) {
+	eps := math.Nextafter(1, 2) - 1
+	for _, test := range []struct {
+		r, c int
+		view bool
+	}{
+		{1, 1, false},
+		{1, 100, false},
+		{1 << 10, 1 << 10, false},
+		{1 << 10, 1 << 10, true},
+		{1 << 20, 1, false},
+	} {
+		// Naive summation of many equal elements that are not exactly
+		// representable accumulates an error linear in the number of
+		// elements.
+		a := NewDense(test.r, test.c, nil)
+		for i := 0; i < test.r; i++ {
+			for j := 0; j < test.c; j++ {
+				a.Set(i, j, 0.1)
+			}
+		}
+		if test.view {
+			// Use a view to exercise non-contiguous rows.
+			b := NewDense(test.r+2, test.c+3, nil)
+			v := b.View(1, 2, test.r, test.c).(*Dense)
+			v.Copy(a)
+			a = v
+		}
+		n := float64(test.r * test.c)
+		want := exactSum(a)
+		got := a.SumPairwise()
+		// The error bound for pairwise summation includes the
+		// sequential summation of the blocks at the leaves.
+		if math.Abs(got-want) > (pairwiseBlock+math.Log2(n))*eps*math.Abs(want) {
+			t.Errorf("unexpected pairwise sum for %d×%d: got: %v want: %v", test.r, test.c, got, want)
+		}
+		if n >= 1<<20 {
+			naive := Sum(a)
+			if !(math.Abs(got-want) < math.Abs(naive-want)) {
+				t.Errorf("pairwise sum not more accurate than naive sum for %d×%d: got: %v naive: %v want: %v",
+					test.r, test.c, got, naive, want)
+			}
+		}
+	}
+}
@@ -6,6 +6,8 @@
 package mat64
 
 import (
+	"math"
+
 	"github.com/gonum/blas/blas64"
 	"github.com/gonum/lapack"
 	"github.com/gonum/lapack/lapack64"
@@ -138,42 +140,143 @@ func (svd *SVD) Values(s []float64) []float64 {
 	return s
 }
 
+// CanonicalizeSigns fixes the arbitrary signs of the computed singular
+// vectors so that the largest magnitude element of each left singular vector
+// is positive, with ties resolved in favor of the element with the lowest
+// index. The corresponding right singular vector is negated along with its
+// left singular vector, so the factorization remains valid. Singular vectors
+// that do not correspond to a singular value are canonicalized independently.
+// Applying CanonicalizeSigns makes the vectors reproducible across
+// factorizations of the same matrix.
+//
+// CanonicalizeSigns will panic if the receiver does not contain a successful
+// factorization.
+func (svd *SVD) CanonicalizeSigns() {
+	if svd.kind == 0 {
+		panic("svd: no decomposition computed")
+	}
+	if svd.kind == matrix.SVDNone {
+		return
+	}
+	u, vt := svd.u, svd.vt
+	k := len(svd.s)
+	for j := 0; j < u.Cols; j++ {
+		if canonicalSign(u.Data[j:], u.Rows, u.Stride) >= 0 {
+			continue
+		}
+		blas64.Scal(u.Rows, -1, blas64.Vector{Inc: u.Stride, Data: u.Data[j:]})
+		if j < k {
+			blas64.Scal(vt.Cols, -1, blas64.Vector{Inc: 1, Data: vt.Data[j*vt.Stride:]})
+		}
+	}
+	for i := k; i < vt.Rows; i++ {
+		if canonicalSign(vt.Data[i*vt.Stride:], vt.Cols, 1) < 0 {
+			blas64.Scal(vt.Cols, -1, blas64.Vector{Inc: 1, Data: vt.Data[i*vt.Stride:]})
+		}
+	}
+}
+
+// canonicalSign returns the sign of the largest magnitude element of the n
+// elements of x with increment inc, or zero if all the elements are zero. If
+// there are several elements with the largest magnitude, the first is used.
+func canonicalSign(x []float64, n, inc int) float64 {
+	var max, sign float64
+	for i := 0; i < n; i++ {
+		v := x[i*inc]
+		if math.Abs(v) > max {
+			max = math.Abs(v)
+			sign = math.Copysign(1, v)
+		}
+	}
+	return sign
+}
+
 // UFromSVD extracts the matrix U from the singular value decomposition, storing
 // the result in-place into the receiver. U is size m×m if svd.Kind() == SVDFull,
 // of size m×min(m,n) if svd.Kind() == SVDThin, and UFromSVD panics otherwise.
+// UFromSVD is equivalent to svd.UTo(m).
 func (m *Dense) UFromSVD(svd *SVD) {
+	svd.UTo(m)
+}
+
+// VFromSVD extracts the matrix V from the singular value decomposition, storing
+// the result in-place into the receiver. V is size n×n if svd.Kind() == SVDFull,
+// of size n×min(m,n) if svd.Kind() == SVDThin, and VFromSVD panics otherwise.
+// VFromSVD is equivalent to svd.VTo(m).
+func (m *Dense) VFromSVD(svd *SVD) {
+	svd.VTo(m)
+}
+
+// UTo extracts the matrix U from the singular value decomposition, storing
+// the result into dst. U is size m×m if svd.Kind() == SVDFull, of size
+// m×min(m,n) if svd.Kind() == SVDThin, and UTo panics otherwise.
+func (svd *SVD) UTo(dst *Dense) {
 	kind := svd.kind
 	if kind != matrix.SVDFull && kind != matrix.SVDThin {
 		panic("mat64: improper SVD kind")
 	}
 	r := svd.u.Rows
 	c := svd.u.Cols
-	m.reuseAs(r, c)
+	dst.reuseAs(r, c)
 
 	tmp := &Dense{
 		mat:     svd.u,
 		capRows: r,
 		capCols: c,
 	}
-	m.Copy(tmp)
+	dst.Copy(tmp)
 }
 
-// VFromSVD extracts the matrix V from the singular value decomposition, storing
-// the result in-place into the receiver. V is size n×n if svd.Kind() == SVDFull,
-// of size n×min(m,n) if svd.Kind() == SVDThin, and VFromSVD panics otherwise.
-func (m *Dense) VFromSVD(svd *SVD) {
+// VTo extracts the matrix V from the singular value decomposition, storing
+// the result into dst. V is size n×n if svd.Kind() == SVDFull, of size
+// n×min(m,n) if svd.Kind() == SVDThin, and VTo panics otherwise.
+func (svd *SVD) VTo(dst *Dense) {
 	kind := svd.kind
 	if kind != matrix.SVDFull && kind != matrix.SVDThin {
 		panic("mat64: improper SVD kind")
 	}
 	r := svd.vt.Rows
 	c := svd.vt.Cols
-	m.reuseAs(c, r)
+	dst.reuseAs(c, r)
 
 	tmp := &Dense{
 		mat:     svd.vt,
 		capRows: r,
 		capCols: c,
 	}
-	m.Copy(tmp.T())
+	dst.Copy(tmp.T())
+}
+
+// ReconstructTo stores into dst the m×n matrix
+//  U * Σ * V^T
+// formed from the factors of the singular value decomposition, recovering the
+// factorized matrix to within rounding error. Only the first min(m,n) singular
+// vectors contribute, so the result is the same for SVDThin and SVDFull.
+// ReconstructTo panics if svd.Kind() is not SVDThin or SVDFull.
+func (svd *SVD) ReconstructTo(dst *Dense) {
+	kind := svd.kind
+	if kind != matrix.SVDFull && kind != matrix.SVDThin {
+		panic("mat64: improper SVD kind")
+	}
+	m := svd.u.Rows
+	n := svd.vt.Cols
+	k := len(svd.s)
+
+	us := getWorkspace(m, k, false)
+	u := &Dense{
+		mat:     svd.u,
+		capRows: svd.u.Rows,
+		capCols: svd.u.Cols,
+	}
+	us.Copy(u.View(0, 0, m, k))
+	for j, sv := range svd.s {
+		blas64.Scal(m, sv, blas64.Vector{Inc: us.mat.Stride, Data: us.mat.Data[j:]})
+	}
+	vt := &Dense{
+		mat:     svd.vt,
+		capRows: svd.vt.Rows,
+		capCols: svd.vt.Cols,
+	}
+	dst.Mul(us, vt.View(0, 0, k, n))
+	putWorkspace(us)
 }
@@ -168,6 +168,133 @@ func TestSVD(t *testing.T) {
 	}
 }
 
+func TestSVDCanonicalizeSigns(t *testing.T) {
+	for _, test := range []struct {
+		m, n int
+		kind matrix.SVDKind
+	}{
+		{5, 5, matrix.SVDFull},
+		{5, 3, matrix.SVDFull},
+		{3, 5, matrix.SVDFull},
+		{7, 4, matrix.SVDThin},
+		{4, 7, matrix.SVDThin},
+	} {
+		m, n := test.m, test.n
+		a := NewDense(m, n, nil)
+		for i := 0; i < m; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, rand.NormFloat64())
+			}
+		}
+
+		var svd1, svd2 SVD
+		if !svd1.Factorize(a, test.kind) || !svd2.Factorize(a, test.kind) {
+			t.Fatalf("SVD factorization failed for %d×%d", m, n)
+		}
+		// Flip the signs of some of the singular vectors of the second
+		// factorization, leaving a valid decomposition of a.
+		u, vt := svd2.u, svd2.vt
+		for j := 0; j < u.Cols; j += 2 {
+			for i := 0; i < u.Rows; i++ {
+				u.Data[i*u.Stride+j] *= -1
+			}
+			if j < len(svd2.s) {
+				for i := 0; i < vt.Cols; i++ {
+					vt.Data[j*vt.Stride+i] *= -1
+				}
+			}
+		}
+		for i := len(svd2.s); i < vt.Rows; i++ {
+			for j := 0; j < vt.Cols; j++ {
+				vt.Data[i*vt.Stride+j] *= -1
+			}
+		}
+
+		svd1.CanonicalizeSigns()
+		svd2.CanonicalizeSigns()
+		s1, u1, v1 := extractSVD(&svd1)
+		_, u2, v2 := extractSVD(&svd2)
+		if !Equal(u1, u2) || !Equal(v1, v2) {
+			t.Errorf("singular vectors differ after sign canonicalization for %d×%d", m, n)
+		}
+
+		for j := 0; j < u1.mat.Cols; j++ {
+			if canonicalSign(u1.mat.Data[j:], u1.mat.Rows, u1.mat.Stride) < 0 {
+				t.Errorf("largest element of left singular vector %d is negative for %d×%d", j, m, n)
+			}
+		}
+		sigma := NewDense(u1.mat.Cols, v1.mat.Cols, nil)
+		for i, v := range s1 {
+			sigma.Set(i, i, v)
+		}
+		var ans Dense
+		ans.Product(u1, sigma, v1.T())
+		if !EqualApprox(&ans, a, 1e-10) {
+			t.Errorf("canonicalized SVD does not reconstruct a for %d×%d", m, n)
+		}
+	}
+}
+
+func TestSVDUToVToReconstructTo(t *testing.T) {
+	for _, test := range []struct {
+		m, n int
+	}{
+		{1, 1},
+		{5, 5},
+		{10, 3},
+		{3, 10},
+	} {
+		a := NewDense(test.m, test.n, nil)
+		for i := 0; i < test.m; i++ {
+			for j := 0; j < test.n; j++ {
+				a.Set(i, j, rand.NormFloat64())
+			}
+		}
+		k := min(test.m, test.n)
+		for _, kind := range []matrix.SVDKind{matrix.SVDThin, matrix.SVDFull} {
+			var svd SVD
+			if !svd.Factorize(a, kind) {
+				t.Errorf("unexpected factorization failure for %d×%d", test.m, test.n)
+				continue
+			}
+			ur, vr := k, k
+			if kind == matrix.SVDFull {
+				ur, vr = test.m, test.n
+			}
+
+			var u, v, uFrom, vFrom Dense
+			svd.UTo(&u)
+			svd.VTo(&v)
+			if r, c := u.Dims(); r != test.m || c != ur {
+				t.Errorf("unexpected U shape for %d×%d kind %v: got: %d×%d want: %d×%d", test.m, test.n, kind, r, c, test.m, ur)
+			}
+			if r, c := v.Dims(); r != test.n || c != vr {
+				t.Errorf("unexpected V shape for %d×%d kind %v: got: %d×%d want: %d×%d", test.m, test.n, kind, r, c, test.n, vr)
+			}
+			uFrom.UFromSVD(&svd)
+			vFrom.VFromSVD(&svd)
+			if !Equal(&u, &uFrom) || !Equal(&v, &vFrom) {
+				t.Errorf("UTo and VTo do not match UFromSVD and VFromSVD for %d×%d kind %v", test.m, test.n, kind)
+			}
+
+			var got Dense
+			svd.ReconstructTo(&got)
+			if !EqualApprox(&got, a, 1e-12) {
+				t.Errorf("unexpected reconstruction for %d×%d kind %v:\ngot:\n%v\nwant:\n%v",
+					test.m, test.n, kind, Formatted(&got), Formatted(a))
+			}
+		}
+	}
+
+	var svd SVD
+	svd.Factorize(NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6}), matrix.SVDNone)
+	for _, fn := range []func(*Dense){svd.UTo, svd.VTo, svd.ReconstructTo} {
+		if p, _ := panics(func() { fn(&Dense{}) }); !p {
+			t.Error("expected panic for SVDNone factorization")
+		}
+	}
+}
+
 func extractSVD(svd *SVD) (s []float64, u, v *Dense) {
 	var um, vm Dense
 	um.UFromSVD(svd)
@@ -5,6 +5,8 @@
 package mat64
 
 import (
+	"math"
+
 	"github.com/gonum/blas"
 	"github.com/gonum/blas/blas64"
 	"github.com/gonum/matrix"
@@ -90,6 +92,11 @@ func (s *SymDense) Symmetric() int {
 
 // RawSymmetric returns the matrix as a blas64.Symmetric. The returned
 // value must be stored in upper triangular format.
+//
+// The returned blas64.Symmetric shares its Data slice with the receiver, so
+// changes to elements of the receiver following the call will be reflected
+// in the returned value and vice versa. Only the upper triangle of Data is
+// referenced by the receiver; elements written below the diagonal are ignored.
 func (s *SymDense) RawSymmetric() blas64.Symmetric {
 	return s.mat
 }
@@ -277,6 +284,38 @@ func (s *SymDense) SymOuterK(alpha float64, x Matrix) {
 	}
 }
 
+// OuterVec calculates the outer product of the vector x with itself and
+// stores the result into the receiver. In order to update an existing
+// matrix, see SymRankOne.
+//  s = alpha * x * x'
+// OuterVec is the symmetric counterpart of Dense.Outer and only computes the
+// upper triangle. If the receiver is not zero it must be len(x)×len(x),
+// otherwise OuterVec will panic with matrix.ErrShape.
+func (s *SymDense) OuterVec(alpha float64, x *Vector) {
+	n := x.Len()
+	switch {
+	case s.isZero():
+		s.mat = blas64.Symmetric{
+			N:      n,
+			Stride: n,
+			Data:   useZeroed(s.mat.Data, n*n),
+			Uplo:   blas.Upper,
+		}
+		s.cap = n
+	case s.mat.Uplo != blas.Upper:
+		panic(badSymTriangle)
+	case s.mat.N == n:
+		// Only zero the upper triangle.
+		for i := 0; i < n; i++ {
+			ri := i * s.mat.Stride
+			zero(s.mat.Data[ri+i : ri+n])
+		}
+	default:
+		panic(matrix.ErrShape)
+	}
+	blas64.Syr(alpha, x.mat, s.mat)
+}
+
 // RankTwo performs a symmmetric rank-two update to the matrix a and stores
 // the result in the receiver
 //  m = a + alpha * (x * y' + y * x')
@@ -321,6 +360,125 @@ func (s *SymDense) ScaleSym(f float64, a Symmetric) {
 	}
 }
 
+// Symmetrize places (a + a^T)/2 in the receiver, forcing exact symmetry of a
+// matrix that is symmetric in theory but may have drifted numerically, for
+// example a covariance computed by a sequence of products. The trace of a is
+// preserved. Symmetrize panics with matrix.ErrSquare if a is not square.
+func (s *SymDense) Symmetrize(a Matrix) {
+	r, c := a.Dims()
+	if r != c {
+		panic(matrix.ErrSquare)
+	}
+	n := r
+	s.reuseAs(n)
+	if a, ok := a.(RawMatrixer); ok {
+		amat := a.RawMatrix()
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				s.mat.Data[i*s.mat.Stride+j] = (amat.Data[i*amat.Stride+j] + amat.Data[j*amat.Stride+i]) / 2
+			}
+		}
+		return
+	}
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			s.mat.Data[i*s.mat.Stride+j] = (a.At(i, j) + a.At(j, i)) / 2
+		}
+	}
+}
+
+// Shrink places (1-lambda)*a + lambda*(tr(a)/n)*I in the receiver, shrinking
+// a toward a scaled identity with the same trace. Shrinkage improves the
+// conditioning of estimated covariance matrices. Shrink panics if lambda
+// is NaN or is not in [0, 1].
+func (s *SymDense) Shrink(a Symmetric, lambda float64) {
+	if !(lambda >= 0 && lambda <= 1) {
+		panic("mat64: shrinkage parameter out of range")
+	}
+	n := a.Symmetric()
+	var tr float64
+	for i := 0; i < n; i++ {
+		tr += a.At(i, i)
+	}
+	s.ScaleSym(1-lambda, a)
+	if n == 0 {
+		return
+	}
+	mu := lambda * tr / float64(n)
+	for i := 0; i < n; i++ {
+		s.mat.Data[i*s.mat.Stride+i] += mu
+	}
+}
+
+// Trace returns the trace of the matrix.
+func (s *SymDense) Trace() float64 {
+	return pairwiseSum(s.mat.Data, s.mat.N, s.mat.Stride+1)
+}
+
+// LogDet returns the log of the determinant of the matrix, computed from its
+// Cholesky factorization. LogDet returns matrix.ErrNotPositiveDefinite if the
+// matrix is not positive definite.
+func (s *SymDense) LogDet() (float64, error) {
+	var chol Cholesky
+	if !chol.Factorize(s) {
+		return 0, matrix.ErrNotPositiveDefinite
+	}
+	return chol.LogDet(), nil
+}
+
+// PseudoInverse places the Moore-Penrose pseudoinverse of the symmetric matrix
+// a into the receiver. The pseudoinverse is computed from the eigendecomposition
+//  a = V * Λ * V^T
+// as
+//  a^+ = V * Λ^+ * V^T
+// where Λ^+ holds the reciprocals of the eigenvalues with magnitude greater
+// than tol and zero in place of the others. The result is symmetric by
+// construction. If a is non-singular and tol is less than the magnitude of its
+// smallest eigenvalue, the pseudoinverse is the inverse of a.
+//
+// PseudoInverse will panic with matrix.ErrNoConvergence if the
+// eigendecomposition of a fails.
+func (s *SymDense) PseudoInverse(a Symmetric, tol float64) {
+	n := a.Symmetric()
+	var eig EigenSym
+	if !eig.Factorize(a, true) {
+		panic(matrix.ErrNoConvergence)
+	}
+	values := eig.Values(nil)
+	var vecs Dense
+	eig.VectorsTo(&vecs)
+
+	s.reuseAs(n)
+	for i := 0; i < n; i++ {
+		zero(s.mat.Data[i*s.mat.Stride+i : i*s.mat.Stride+n])
+	}
+	for j, l := range values {
+		if math.Abs(l) <= tol {
+			continue
+		}
+		blas64.Syr(1/l, vecs.ColView(j).mat, s.mat)
+	}
+}
+
+// IsPositiveDefinite returns whether the symmetric matrix a is positive
+// definite, as determined by the success of its Cholesky factorization.
+func IsPositiveDefinite(a Symmetric) bool {
+	var chol Cholesky
+	return chol.Factorize(a)
+}
+
+// IsPositiveSemidefinite returns whether the symmetric matrix a is positive
+// semidefinite, that is whether its smallest eigenvalue is not less than -tol.
+func IsPositiveSemidefinite(a Symmetric, tol float64) bool {
+	var eig Eigen
+	if !eig.Factorize(a, false) {
+		return false
+	}
+	// The eigenvalues of a symmetric matrix are real and
+	// are returned in ascending order.
+	return real(eig.Values(nil)[0]) >= -tol
+}
+
 // SubsetSym extracts a subset of the rows and columns of the matrix a and stores
 // the result in-place into the receiver. The resulting matrix size is
 // len(set)×len(set). Specifically, at the conclusion of SubsetSym,
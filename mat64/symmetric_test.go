@@ -6,6 +6,7 @@ package mat64
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"os"
 	"reflect"
@@ -129,6 +130,31 @@ func TestSymAtSet(t *testing.T) {
 	}
 }
 
+func TestRawSymmetric(t *testing.T) {
+	s := NewSymDense(3, []float64{
+		1, 2, 3,
+		2, 4, 5,
+		3, 5, 6,
+	})
+	raw := s.RawSymmetric()
+	if raw.N != 3 || raw.Uplo != blas.Upper {
+		t.Errorf("unexpected raw symmetric header: %+v", raw)
+	}
+
+	// Mutating the upper triangle through the raw view must be
+	// reflected in both symmetric elements of the matrix.
+	raw.Data[0*raw.Stride+2] = 10
+	if v, vt := s.At(0, 2), s.At(2, 0); v != 10 || vt != 10 {
+		t.Errorf("unexpected values after raw update: got: (%v, %v) want: (10, 10)", v, vt)
+	}
+
+	// Mutating through the matrix must be reflected in the raw view.
+	s.SetSym(2, 1, -1)
+	if v := raw.Data[1*raw.Stride+2]; v != -1 {
+		t.Errorf("unexpected raw value after matrix update: got: %v want: -1", v)
+	}
+}
+
 func TestSymAdd(t *testing.T) {
 	for _, test := range []struct {
 		n int
@@ -434,6 +460,56 @@ func TestSymOuterK(t *testing.T) {
 	}
 }
 
+func TestSymOuterVec(t *testing.T) {
+	for _, test := range []struct {
+		x     []float64
+		alpha float64
+	}{
+		{x: []float64{2}, alpha: 3},
+		{x: []float64{1, -2, 3}, alpha: 1},
+		{x: []float64{1, 2, 3, 4, 5}, alpha: -0.5},
+	} {
+		x := NewVector(len(test.x), test.x)
+		var want Dense
+		want.Outer(test.alpha, x, x)
+
+		var s SymDense
+		s.OuterVec(test.alpha, x)
+		if !Equal(&s, &want) {
+			t.Errorf("unexpected outer product of %v:\ngot:\n%v\nwant:\n%v", test.x, Formatted(&s), Formatted(&want))
+		}
+
+		// Check that a non-zero receiver is overwritten.
+		n := len(test.x)
+		s = *NewSymDense(n, nil)
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				s.SetSym(i, j, 100)
+			}
+		}
+		s.OuterVec(test.alpha, x)
+		if !Equal(&s, &want) {
+			t.Errorf("unexpected outer product of %v into non-zero receiver:\ngot:\n%v\nwant:\n%v", test.x, Formatted(&s), Formatted(&want))
+		}
+
+		// Check with a non-unit increment vector.
+		data := make([]float64, 2*n)
+		for i, v := range test.x {
+			data[2*i] = v
+		}
+		xInc := NewDense(n, 2, data).ColView(0)
+		s.OuterVec(test.alpha, xInc)
+		if !Equal(&s, &want) {
+			t.Errorf("unexpected outer product of strided %v:\ngot:\n%v\nwant:\n%v", test.x, Formatted(&s), Formatted(&want))
+		}
+	}
+
+	panicked, message := panics(func() { NewSymDense(2, nil).OuterVec(1, NewVector(3, nil)) })
+	if !panicked || message != matrix.ErrShape.Error() {
+		t.Errorf("unexpected panic for mismatched receiver: got: %q want: %q", message, matrix.ErrShape)
+	}
+}
+
 func TestIssue250SymOuterK(t *testing.T) {
 	x := NewVector(5, []float64{1, 2, 3, 4, 5})
 	var s1, s2 SymDense
@@ -461,6 +537,275 @@ func TestScaleSym(t *testing.T) {
 	}
 }
 
+func TestSymmetrize(t *testing.T) {
+	for _, a := range []Matrix{
+		NewDense(3, 3, []float64{
+			1, 2 + 1e-15, 3,
+			2, 4, 5 - 1e-14,
+			3 + 1e-16, 5, 6,
+		}),
+		NewDense(3, 3, []float64{
+			1, 2, 3,
+			4, 5, 6,
+			7, 8, 9,
+		}).T(),
+		NewSymDense(2, []float64{
+			1, 2,
+			2, 3,
+		}),
+	} {
+		n, _ := a.Dims()
+		var s SymDense
+		s.Symmetrize(a)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				if s.At(i, j) != s.At(j, i) {
+					t.Errorf("result not symmetric at (%d,%d): %v != %v", i, j, s.At(i, j), s.At(j, i))
+				}
+				want := (a.At(i, j) + a.At(j, i)) / 2
+				if s.At(i, j) != want {
+					t.Errorf("unexpected element at (%d,%d): got: %v want: %v", i, j, s.At(i, j), want)
+				}
+			}
+		}
+		if tr := Trace(a); s.Trace() != tr {
+			t.Errorf("trace not preserved: got: %v want: %v", s.Trace(), tr)
+		}
+	}
+
+	var s SymDense
+	if p, _ := panics(func() { s.Symmetrize(NewDense(2, 3, nil)) }); !p {
+		t.Error("expected panic for non-square input")
+	}
+}
+
+func TestShrink(t *testing.T) {
+	a := NewSymDense(3, []float64{
+		4, 2, 2,
+		2, 2.01, 1,
+		2, 1, 2,
+	})
+	n := a.Symmetric()
+	minEigen := func(a Symmetric) float64 {
+		var eig Eigen
+		if !eig.Factorize(a, false) {
+			t.Fatal("unexpected eigendecomposition failure")
+		}
+		return real(eig.Values(nil)[0])
+	}
+	prev := minEigen(a)
+	for _, lambda := range []float64{0, 0.1, 0.5, 0.9, 1} {
+		var s SymDense
+		s.Shrink(a, lambda)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				want := (1 - lambda) * a.At(i, j)
+				if i == j {
+					want += lambda * a.Trace() / float64(n)
+				}
+				if math.Abs(s.At(i, j)-want) > 1e-14 {
+					t.Errorf("unexpected element at (%d,%d) for lambda=%v: got: %v want: %v", i, j, lambda, s.At(i, j), want)
+				}
+			}
+		}
+		if math.Abs(s.Trace()-a.Trace()) > 1e-14 {
+			t.Errorf("trace not preserved for lambda=%v: got: %v want: %v", lambda, s.Trace(), a.Trace())
+		}
+		min := minEigen(&s)
+		if lambda != 0 && min <= prev {
+			t.Errorf("smallest eigenvalue did not increase for lambda=%v: got: %v previous: %v", lambda, min, prev)
+		}
+		prev = min
+	}
+
+	s := NewSymDense(n, nil)
+	s.CopySym(a)
+	s.Shrink(s, 0.5)
+	var want SymDense
+	want.Shrink(a, 0.5)
+	if !Equal(s, &want) {
+		t.Errorf("unexpected result shrinking in place: got: %v want: %v", Formatted(s), Formatted(&want))
+	}
+
+	for _, lambda := range []float64{-0.1, 1.1, math.NaN()} {
+		var s SymDense
+		if p, _ := panics(func() { s.Shrink(a, lambda) }); !p {
+			t.Errorf("expected panic for lambda=%v", lambda)
+		}
+	}
+}
+
+func TestSymTraceLogDet(t *testing.T) {
+	d := []float64{2, 0.5, 4, 3}
+	s := NewSymDense(4, nil)
+	var wantTrace, wantLogDet float64
+	for i, v := range d {
+		s.SetSym(i, i, v)
+		wantTrace += v
+		wantLogDet += math.Log(v)
+	}
+	if tr := s.Trace(); tr != wantTrace {
+		t.Errorf("unexpected trace: got: %v want: %v", tr, wantTrace)
+	}
+	logDet, err := s.LogDet()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if math.Abs(logDet-wantLogDet) > 1e-14 {
+		t.Errorf("unexpected log determinant: got: %v want: %v", logDet, wantLogDet)
+	}
+
+	// A dense SPD matrix must agree with the general LogDet.
+	a := NewSymDense(3, []float64{
+		4, 1, 2,
+		1, 5, 1,
+		2, 1, 6,
+	})
+	logDet, err = a.LogDet()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if want, _ := LogDet(a); math.Abs(logDet-want) > 1e-13 {
+		t.Errorf("unexpected log determinant: got: %v want: %v", logDet, want)
+	}
+	if tr := a.Trace(); tr != 15 {
+		t.Errorf("unexpected trace: got: %v want: 15", tr)
+	}
+
+	indef := NewSymDense(2, []float64{1, 2, 2, 1})
+	if _, err := indef.LogDet(); err != matrix.ErrNotPositiveDefinite {
+		t.Errorf("unexpected error for indefinite matrix: got: %v want: %v", err, matrix.ErrNotPositiveDefinite)
+	}
+}
+
+func TestSymPseudoInverse(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 10} {
+		g := NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				g.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		a := NewSymDense(n, nil)
+		a.SymOuterK(1, g)
+		for i := 0; i < n; i++ {
+			a.SetSym(i, i, a.At(i, i)+1)
+		}
+
+		// For a full rank matrix the pseudoinverse is the inverse.
+		var pinv SymDense
+		pinv.PseudoInverse(a, 1e-10)
+		var inv Dense
+		if err := inv.Inverse(a); err != nil {
+			t.Fatalf("unexpected error inverting matrix for n=%d: %v", n, err)
+		}
+		if !EqualApprox(&pinv, &inv, 1e-10) {
+			t.Errorf("pseudoinverse of full rank matrix differs from inverse for n=%d:\ngot:\n%v\nwant:\n%v",
+				n, Formatted(&pinv), Formatted(&inv))
+		}
+
+		// A rank deficient matrix with positive and negative eigenvalues.
+		k := (n + 1) / 2
+		b := NewSymDense(n, nil)
+		for j := 0; j < k; j++ {
+			alpha := 1.0
+			if j%2 == 1 {
+				alpha = -2
+			}
+			b.SymRankOne(b, alpha, g.ColView(j))
+		}
+		pinv.PseudoInverse(b, 1e-8)
+
+		// Check the Moore-Penrose conditions
+		//  B * B^+ * B = B
+		//  B^+ * B * B^+ = B^+
+		// and that B * B^+ is symmetric.
+		var bp, got Dense
+		bp.Mul(b, &pinv)
+		got.Mul(&bp, b)
+		if !EqualApprox(&got, b, 1e-8) {
+			t.Errorf("B * B^+ * B != B for n=%d", n)
+		}
+		got.Mul(&pinv, &bp)
+		if !EqualApprox(&got, &pinv, 1e-8) {
+			t.Errorf("B^+ * B * B^+ != B^+ for n=%d", n)
+		}
+		if !EqualApprox(&bp, bp.T(), 1e-8) {
+			t.Errorf("B * B^+ not symmetric for n=%d", n)
+		}
+	}
+
+	// The pseudoinverse of the zero matrix is zero, and the receiver is
+	// overwritten.
+	s := NewSymDense(3, []float64{
+		1, 2, 3,
+		2, 4, 5,
+		3, 5, 6,
+	})
+	s.PseudoInverse(NewSymDense(3, nil), 1e-10)
+	if !Equal(s, NewSymDense(3, nil)) {
+		t.Errorf("unexpected pseudoinverse of zero matrix:\n%v", Formatted(s))
+	}
+}
+
+func TestIsPositiveDefinite(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		a      *SymDense
+		pd     bool
+		psd    bool
+		tolPSD bool
+	}{
+		{
+			name: "positive definite",
+			a: NewSymDense(3, []float64{
+				4, 1, 0,
+				1, 3, 1,
+				0, 1, 2,
+			}),
+			pd: true, psd: true, tolPSD: true,
+		},
+		{
+			// The outer product of {1, 2, 3} has rank one.
+			name: "singular positive semidefinite",
+			a: NewSymDense(3, []float64{
+				1, 2, 3,
+				2, 4, 6,
+				3, 6, 9,
+			}),
+			pd: false, psd: true, tolPSD: true,
+		},
+		{
+			name: "indefinite",
+			a: NewSymDense(2, []float64{
+				1, 2,
+				2, 1,
+			}),
+			pd: false, psd: false, tolPSD: false,
+		},
+		{
+			// The smallest eigenvalue is -1e-10.
+			name: "slightly indefinite",
+			a: NewSymDense(2, []float64{
+				1, 0,
+				0, -1e-10,
+			}),
+			pd: false, psd: false, tolPSD: true,
+		},
+	} {
+		if pd := IsPositiveDefinite(test.a); pd != test.pd {
+			t.Errorf("unexpected positive definiteness for %s matrix: got: %t want: %t", test.name, pd, test.pd)
+		}
+		if psd := IsPositiveSemidefinite(test.a, 1e-14); psd != test.psd {
+			t.Errorf("unexpected positive semidefiniteness for %s matrix: got: %t want: %t", test.name, psd, test.psd)
+		}
+		if psd := IsPositiveSemidefinite(test.a, 1e-8); psd != test.tolPSD {
+			t.Errorf("unexpected positive semidefiniteness with tolerance for %s matrix: got: %t want: %t", test.name, psd, test.tolPSD)
+		}
+	}
+}
+
 func TestSubsetSym(t *testing.T) {
 	for _, test := range []struct {
 		a    *SymDense
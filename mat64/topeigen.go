@@ -0,0 +1,180 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+
+	"github.com/gonum/matrix"
+)
+
+// TopEigen computes the k eigenvalues of the n×n symmetric matrix a with the
+// largest magnitude, and their eigenvectors, using power iteration with
+// Hotelling deflation. Each eigenpair is found by power iteration on a with
+// the previously found eigenpairs subtracted, that is on
+//  a - Σ λ_i * v_i * v_i^T.
+// The eigenvalues are returned in order of decreasing magnitude, and column i
+// of vectors is the unit eigenvector of values[i].
+//
+// Iteration for each eigenpair stops when the residual ||B*v - λ*v|| is at
+// most tol*|λ_1|, where B is the deflated matrix, λ is the Rayleigh quotient
+// of the unit vector v and λ_1 is the dominant eigenvalue, or its current
+// estimate while it is being found. The tolerance is relative to the dominant
+// eigenvalue because errors in each eigenpair are carried into the deflated
+// matrix used to find the next. If any eigenpair does not converge within maxIter
+// iterations, TopEigen returns matrix.ErrNoConvergence along with the
+// eigenpairs found so far. Power iteration converges slowly or not at all when
+// the magnitudes of successive eigenvalues are close or equal, so TopEigen is
+// best suited to matrices with well separated dominant eigenvalues.
+//
+// TopEigen will panic if k is not in [1, n].
+func TopEigen(a Symmetric, k int, tol float64, maxIter int) (values []float64, vectors *Dense, err error) {
+	n := a.Symmetric()
+	if k < 1 || n < k {
+		panic(matrix.ErrIndexOutOfRange)
+	}
+	b := NewSymDense(n, nil)
+	b.CopySym(a)
+
+	vectors = NewDense(n, k, nil)
+	v := NewVector(n, nil)
+	w := NewVector(n, nil)
+	r := NewVector(n, nil)
+	for found := 0; found < k; found++ {
+		// Use a fixed starting vector so the result is reproducible,
+		// chosen to be unlikely to be orthogonal to any eigenvector.
+		for i := 0; i < n; i++ {
+			v.SetVec(i, 1+float64(i)/float64(n))
+		}
+		orthonormalize(v, vectors, found)
+
+		var lambda float64
+		converged := false
+		for iter := 0; iter < maxIter; iter++ {
+			w.MulVec(b, v)
+			lambda = Dot(v, w)
+			r.AddScaledVec(w, -lambda, v)
+			scale := math.Abs(lambda)
+			if found > 0 {
+				scale = math.Abs(values[0])
+			}
+			if Norm(r, 2) <= tol*scale {
+				converged = true
+				break
+			}
+			// Remove any components along the found eigenvectors
+			// that reappear due to rounding.
+			v.CopyVec(w)
+			orthonormalize(v, vectors, found)
+		}
+		if !converged {
+			if found == 0 {
+				return nil, nil, matrix.ErrNoConvergence
+			}
+			return values, vectors.View(0, 0, n, found).(*Dense), matrix.ErrNoConvergence
+		}
+
+		values = append(values, lambda)
+		vectors.ColView(found).CopyVec(v)
+		b.SymRankOne(b, -lambda, v)
+	}
+	return values, vectors, nil
+}
+
+// orthonormalize removes the components of v along the first c orthonormal
+// columns of q and normalizes the result.
+func orthonormalize(v *Vector, q *Dense, c int) {
+	for j := 0; j < c; j++ {
+		qj := q.ColView(j)
+		v.AddScaledVec(v, -Dot(v, qj), qj)
+	}
+	v.ScaleVec(1/Norm(v, 2), v)
+}
+
+// RayleighQuotient refines an approximate eigenvector v0 of the n×n symmetric
+// matrix a using Rayleigh quotient iteration, returning the eigenvalue lambda
+// and unit eigenvector v of the eigenpair it converges to. At each iteration
+// the estimate of the eigenvalue is the Rayleigh quotient
+//  λ = v^T * A * v
+// of the current unit vector v, and the next vector is the normalized
+// solution w of the shifted system
+//  (A - λ*I) * w = v.
+// Convergence is locally cubic, but the eigenpair found is the one nearest
+// the initial guess, which is not necessarily the closest in eigenvalue to
+// the Rayleigh quotient of v0. The LU factorization of the shifted matrix is
+// computed into storage that is reused between iterations.
+//
+// Iteration stops when the residual ||A*v - λ*v|| is at most tol. If this does
+// not occur within maxIter iterations, RayleighQuotient returns
+// matrix.ErrNoConvergence along with the final estimate of the eigenpair. No
+// iterations are performed if maxIter is not positive.
+// If the shifted matrix is exactly singular before the residual criterion is
+// met, the shift is perturbed slightly, and if it remains singular
+// matrix.ErrSingular is returned with the current estimate.
+//
+// RayleighQuotient will panic with matrix.ErrShape if the length of v0 is not
+// n, and with matrix.ErrZeroMatrix if v0 is zero. The input v0 is not modified.
+func RayleighQuotient(a Symmetric, v0 *Vector, tol float64, maxIter int) (lambda float64, v *Vector, err error) {
+	n := a.Symmetric()
+	if v0.Len() != n {
+		panic(matrix.ErrShape)
+	}
+	norm := Norm(v0, 2)
+	if norm == 0 {
+		panic(matrix.ErrZeroMatrix)
+	}
+	v = NewVector(n, nil)
+	v.ScaleVec(1/norm, v0)
+
+	var (
+		ws    Workspace
+		lu    LU
+		shift = NewDense(n, n, nil)
+		av    = NewVector(n, nil)
+		r     = NewVector(n, nil)
+	)
+	for iter := 0; ; iter++ {
+		av.MulVec(a, v)
+		lambda = Dot(v, av)
+		r.AddScaledVec(av, -lambda, v)
+		if Norm(r, 2) <= tol {
+			return lambda, v, nil
+		}
+		if iter >= maxIter {
+			return lambda, v, matrix.ErrNoConvergence
+		}
+
+		shiftBy(shift, a, lambda)
+		lu.FactorizeInto(shift, &ws)
+		if lu.Det() == 0 {
+			// The Rayleigh quotient is an eigenvalue to working
+			// precision, so perturb the shift to obtain a solvable
+			// system whose solution is dominated by the eigenvector.
+			shiftBy(shift, a, lambda+rqPerturbation*math.Max(1, math.Abs(lambda)))
+			lu.FactorizeInto(shift, &ws)
+			if lu.Det() == 0 {
+				return lambda, v, matrix.ErrSingular
+			}
+		}
+		// The shifted matrix becomes increasingly ill-conditioned as
+		// the iteration converges, so a Condition error is expected
+		// and the solution is still a good eigenvector estimate.
+		v.SolveLUVec(&lu, false, v)
+		v.ScaleVec(1/Norm(v, 2), v)
+	}
+}
+
+// rqPerturbation is the relative perturbation applied by RayleighQuotient to
+// a shift that makes the shifted matrix exactly singular.
+const rqPerturbation = 1e-12
+
+// shiftBy stores a - lambda*I into dst.
+func shiftBy(dst *Dense, a Matrix, lambda float64) {
+	dst.Copy(a)
+	n, _ := dst.Dims()
+	for i := 0; i < n; i++ {
+		dst.set(i, i, dst.at(i, i)-lambda)
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/matrix"
+)
+
+func TestTopEigen(t *testing.T) {
+	// Construct a symmetric matrix with known, well separated
+	// eigenvalues from a random orthonormal basis.
+	const n = 6
+	want := []float64{10, -6, 3, 1.5, -0.5, 0.1}
+	g := NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			g.Set(i, j, rand.NormFloat64())
+		}
+	}
+	var qr QR
+	qr.Factorize(g)
+	var q Dense
+	q.QFromQR(&qr)
+	a := NewSymDense(n, nil)
+	for i, l := range want {
+		a.SymRankOne(a, l, q.ColView(i))
+	}
+
+	// Compare against the full eigendecomposition, matching
+	// each eigenvalue found to the closest in the full set.
+	var eig Eigen
+	eig.Factorize(a, true)
+	full := eig.Values(nil)
+	vecs := eig.Vectors()
+	closest := func(v float64) int {
+		var j int
+		for i, f := range full {
+			if math.Abs(real(f)-v) < math.Abs(real(full[j])-v) {
+				j = i
+			}
+		}
+		return j
+	}
+
+	for _, k := range []int{1, 3, n} {
+		values, vectors, err := TopEigen(a, k, 1e-12, 1000)
+		if err != nil {
+			t.Fatalf("unexpected error for k=%d: %v", k, err)
+		}
+		if len(values) != k {
+			t.Fatalf("unexpected number of eigenvalues for k=%d: got: %d", k, len(values))
+		}
+		for i, v := range values {
+			if math.Abs(v-want[i]) > 1e-10 {
+				t.Errorf("eigenvalue %d for k=%d out of order: got: %v want: %v", i, k, v, want[i])
+			}
+			j := closest(v)
+			if math.Abs(v-real(full[j])) > 1e-10 {
+				t.Errorf("unexpected eigenvalue %d for k=%d: got: %v want: %v", i, k, v, real(full[j]))
+			}
+			// Eigenvectors are only defined up to sign.
+			if d := Dot(vectors.ColView(i), vecs.ColView(j)); math.Abs(math.Abs(d)-1) > 1e-8 {
+				t.Errorf("unexpected eigenvector %d for k=%d: |dot| = %v", i, k, math.Abs(d))
+			}
+			var av Vector
+			av.MulVec(a, vectors.ColView(i))
+			av.AddScaledVec(&av, -v, vectors.ColView(i))
+			if r := Norm(&av, 2); r > 1e-10 {
+				t.Errorf("unexpected residual for eigenpair %d for k=%d: %v", i, k, r)
+			}
+		}
+	}
+
+	// Equal magnitude eigenvalues prevent convergence.
+	b := NewSymDense(2, []float64{
+		0, 1,
+		1, 0,
+	})
+	values, vectors, err := TopEigen(b, 1, 1e-12, 100)
+	if err != matrix.ErrNoConvergence {
+		t.Errorf("unexpected error for non-convergent matrix: got: %v want: %v", err, matrix.ErrNoConvergence)
+	}
+	if values != nil || vectors != nil {
+		t.Errorf("unexpected eigenpairs for non-convergent matrix: %v %v", values, vectors)
+	}
+
+	for _, k := range []int{0, n + 1} {
+		if p, _ := panics(func() { TopEigen(a, k, 1e-12, 100) }); !p {
+			t.Errorf("expected panic for k=%d", k)
+		}
+	}
+}
+
+func TestRayleighQuotient(t *testing.T) {
+	const n = 8
+	want := []float64{9, 5, 4, 2.5, 1, -1, -3, -7}
+	// Rayleigh quotient iteration may converge to a neighbouring eigenpair
+	// from a poor enough guess, so use a fixed seed.
+	rnd := rand.New(rand.NewSource(1))
+	g := NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			g.Set(i, j, rnd.NormFloat64())
+		}
+	}
+	var qr QR
+	qr.Factorize(g)
+	var q Dense
+	q.QFromQR(&qr)
+	a := NewSymDense(n, nil)
+	for i, l := range want {
+		a.SymRankOne(a, l, q.ColView(i))
+	}
+
+	for k := range want {
+		// Start from a rough guess at eigenvector k, perturbing
+		// it by a random vector of a quarter of its norm.
+		v0 := NewVector(n, nil)
+		for i := 0; i < n; i++ {
+			v0.SetVec(i, rnd.NormFloat64())
+		}
+		v0.ScaleVec(1/(4*Norm(v0, 2)), v0)
+		v0.AddVec(v0, q.ColView(k))
+		orig := NewVector(n, nil)
+		orig.CopyVec(v0)
+
+		lambda, v, err := RayleighQuotient(a, v0, 1e-12, 10)
+		if err != nil {
+			t.Errorf("unexpected error for eigenpair %d: %v", k, err)
+			continue
+		}
+		if math.Abs(lambda-want[k]) > 1e-10 {
+			t.Errorf("unexpected eigenvalue %d: got: %v want: %v", k, lambda, want[k])
+		}
+		if math.Abs(Norm(v, 2)-1) > 1e-14 {
+			t.Errorf("eigenvector %d not normalized: norm = %v", k, Norm(v, 2))
+		}
+		// Eigenvectors are only defined up to sign.
+		if d := Dot(v, q.ColView(k)); math.Abs(math.Abs(d)-1) > 1e-10 {
+			t.Errorf("unexpected eigenvector %d: |dot| = %v", k, math.Abs(d))
+		}
+		if !Equal(v0, orig) {
+			t.Errorf("initial vector %d modified", k)
+		}
+	}
+
+	v0 := NewVector(n, nil)
+	v0.CopyVec(q.ColView(0))
+	v0.AddVec(v0, q.ColView(1))
+	if _, _, err := RayleighQuotient(a, v0, 1e-12, 0); err != matrix.ErrNoConvergence {
+		t.Errorf("unexpected error with no iterations: got: %v want: %v", err, matrix.ErrNoConvergence)
+	}
+
+	if _, _, err := RayleighQuotient(a, v0, 1e-12, -1); err != matrix.ErrNoConvergence {
+		t.Errorf("unexpected error with negative iteration limit: got: %v want: %v", err, matrix.ErrNoConvergence)
+	}
+
+	for _, test := range []struct {
+		v0   *Vector
+		want error
+	}{
+		{v0: NewVector(n-1, nil), want: matrix.ErrShape},
+		{v0: NewVector(n, nil), want: matrix.ErrZeroMatrix},
+	} {
+		panicked, message := panics(func() { RayleighQuotient(a, test.v0, 1e-12, 10) })
+		if !panicked || message != test.want.Error() {
+			t.Errorf("unexpected panic for initial vector of length %d: got: %q want: %q", test.v0.Len(), message, test.want)
+		}
+	}
+}
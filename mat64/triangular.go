@@ -33,6 +33,7 @@ type Triangular interface {
 	TTri() Triangular
 }
 
+// A RawTriangular can return a view of itself as a BLAS Triangular matrix.
 type RawTriangular interface {
 	RawTriangular() blas64.Triangular
 }
@@ -168,6 +169,12 @@ func (t *TriDense) TTri() Triangular {
 	return TransposeTri{t}
 }
 
+// RawTriangular returns the underlying blas64.Triangular used by the receiver.
+//
+// The returned blas64.Triangular shares its Data slice with the receiver, so
+// changes to elements of the receiver following the call will be reflected
+// in the returned value and vice versa. Only the triangle given by Uplo is
+// referenced by the receiver; elements written in the other triangle are ignored.
 func (t *TriDense) RawTriangular() blas64.Triangular {
 	return t.mat
 }
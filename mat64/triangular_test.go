@@ -135,6 +135,39 @@ func TestTriAtSet(t *testing.T) {
 	}
 }
 
+func TestRawTriangular(t *testing.T) {
+	tri := NewTriDense(3, true, []float64{
+		1, 2, 3,
+		0, 4, 5,
+		0, 0, 6,
+	})
+	raw := tri.RawTriangular()
+	if raw.N != 3 || raw.Uplo != blas.Upper {
+		t.Errorf("unexpected raw triangular header: %+v", raw)
+	}
+
+	// Mutating through the raw view must be reflected in the matrix.
+	raw.Data[1*raw.Stride+2] = 10
+	if v := tri.At(1, 2); v != 10 {
+		t.Errorf("unexpected value after raw update: got: %v want: 10", v)
+	}
+	blas64.Scal(raw.N*raw.Stride, 2, blas64.Vector{Inc: 1, Data: raw.Data})
+	want := NewTriDense(3, true, []float64{
+		2, 4, 6,
+		0, 8, 20,
+		0, 0, 12,
+	})
+	if !Equal(tri, want) {
+		t.Errorf("unexpected matrix after raw scaling:\ngot:\n%v\nwant:\n%v", Formatted(tri), Formatted(want))
+	}
+
+	// Mutating through the matrix must be reflected in the raw view.
+	tri.SetTri(0, 1, -1)
+	if v := raw.Data[1]; v != -1 {
+		t.Errorf("unexpected raw value after matrix update: got: %v want: -1", v)
+	}
+}
+
 func TestTriDenseCopy(t *testing.T) {
 	for i := 0; i < 100; i++ {
 		size := rand.Intn(100)
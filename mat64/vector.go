@@ -5,8 +5,12 @@
 package mat64
 
 import (
+	"math"
+	"sort"
+
 	"github.com/gonum/blas"
 	"github.com/gonum/blas/blas64"
+	"github.com/gonum/floats"
 	"github.com/gonum/internal/asm"
 	"github.com/gonum/matrix"
 )
@@ -265,6 +269,134 @@ func (v *Vector) DivElemVec(a, b *Vector) {
 	}
 }
 
+// Cosine returns the cosine of the angle between the vectors a and b. If
+// either vector has zero length, Cosine returns NaN. Cosine will panic with
+// matrix.ErrShape if a and b do not have the same length.
+func Cosine(a, b *Vector) float64 {
+	n := a.Len()
+	if b.Len() != n {
+		panic(matrix.ErrShape)
+	}
+	na := blas64.Nrm2(n, a.mat)
+	nb := blas64.Nrm2(n, b.mat)
+	if na == 0 || nb == 0 {
+		return math.NaN()
+	}
+	cos := blas64.Dot(n, a.mat, b.mat) / (na * nb)
+	// Rounding may take the ratio slightly outside [-1, 1].
+	return math.Max(-1, math.Min(1, cos))
+}
+
+// Angle returns the angle in radians between the vectors a and b, in the
+// interval [0, π]. If either vector has zero length, Angle returns NaN.
+// Angle will panic with matrix.ErrShape if a and b do not have the same
+// length.
+func Angle(a, b *Vector) float64 {
+	return math.Acos(Cosine(a, b))
+}
+
+// Project places the orthogonal projection of a onto the direction of the
+// vector onto into the receiver. If onto is the zero vector, the projection
+// is the zero vector. Project will panic with matrix.ErrShape if a and onto
+// do not have the same length.
+func (v *Vector) Project(a, onto *Vector) {
+	n := a.Len()
+	if onto.Len() != n {
+		panic(matrix.ErrShape)
+	}
+	var alpha float64
+	if oo := blas64.Dot(n, onto.mat, onto.mat); oo != 0 {
+		alpha = blas64.Dot(n, a.mat, onto.mat) / oo
+	}
+	v.ScaleVec(alpha, onto)
+}
+
+// Normalize scales a to have unit p-norm, placing the result in the receiver.
+// If a has a p-norm of zero, Normalize returns matrix.ErrZeroMatrix and the
+// receiver is not modified. Normalize will panic with matrix.ErrNormOrder if
+// p is not positive.
+func (v *Vector) Normalize(a *Vector, p float64) error {
+	if !(p > 0) {
+		panic(matrix.ErrNormOrder)
+	}
+	norm := floats.Norm(Col(nil, 0, a), p)
+	if norm == 0 {
+		return matrix.ErrZeroMatrix
+	}
+	v.ScaleVec(1/norm, a)
+	return nil
+}
+
+// ProjectSimplex places the Euclidean projection of a onto the probability
+// simplex, the set of vectors with non-negative elements summing to one, into
+// the receiver. The projection is computed by the sorting-based method of
+// Held, Wolfe and Crowder, and is
+//  v[i] = max(a[i]-θ, 0)
+// where θ is the unique threshold making the elements of v sum to one.
+func (v *Vector) ProjectSimplex(a *Vector) {
+	x := Col(nil, 0, a)
+	u := make([]float64, len(x))
+	copy(u, x)
+	sort.Sort(sort.Reverse(sort.Float64Slice(u)))
+
+	// Find the largest k for which u[k-1] remains positive
+	// after subtracting the threshold implied by u[:k].
+	var sum, theta float64
+	for k, e := range u {
+		sum += e
+		t := (sum - 1) / float64(k+1)
+		if e-t <= 0 {
+			break
+		}
+		theta = t
+	}
+
+	v.reuseAs(len(x))
+	for i, e := range x {
+		v.SetVec(i, math.Max(e-theta, 0))
+	}
+}
+
+// MeanVec places the weighted mean of the rows of a into the receiver. If
+// weights is nil, the rows are weighted equally, otherwise len(weights) must
+// equal the number of rows of a and MeanVec will panic with matrix.ErrShape
+// if it does not. If the weights sum to zero, every element of the result is
+// NaN.
+func (v *Vector) MeanVec(a Matrix, weights []float64) {
+	r, c := a.Dims()
+	if weights != nil && len(weights) != r {
+		panic(matrix.ErrShape)
+	}
+
+	mean := make([]float64, c)
+	row := make([]float64, c)
+	sumW := float64(r)
+	if weights != nil {
+		sumW = floats.Sum(weights)
+	}
+	for i := 0; i < r; i++ {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		floats.AddScaled(mean, w, Row(row, i, a))
+	}
+	if sumW == 0 {
+		// Scaling by 1/sumW would give ±Inf for components
+		// with a non-zero weighted sum.
+		for i := range mean {
+			mean[i] = math.NaN()
+		}
+	} else {
+		floats.Scale(1/sumW, mean)
+	}
+
+	v.reuseAs(c)
+	for i, m := range mean {
+		v.SetVec(i, m)
+	}
+}
+
 // MulVec computes a * b. The result is stored into the receiver.
 // MulVec panics if the number of columns in a does not equal the number of rows in b.
 func (v *Vector) MulVec(a Matrix, b *Vector) {
@@ -328,6 +460,24 @@ func (v *Vector) MulVec(a Matrix, b *Vector) {
 			t = blas.Trans
 		}
 		blas64.Gemv(t, 1, amat, b.mat, 0, v.mat)
+	case RawBander:
+		amat := a.RawBand()
+		if amat.Cols > amat.Rows || amat.Rows > amat.Cols+amat.KL {
+			// The native Dgbmv bounds each row of the band by
+			// min(m, n) rather than n, which drops elements of
+			// wide matrices and fails for tall matrices with
+			// rows entirely outside the band, so compute the
+			// product directly for those shapes.
+			bandMulVec(v.mat, trans, amat, b.mat)
+			return
+		}
+		t := blas.NoTrans
+		if trans {
+			t = blas.Trans
+		}
+		blas64.Gbmv(t, 1, amat, b.mat, 0, v.mat)
+	case *CSR:
+		a.mulVecTo(v, trans, b)
 	case Vectorer:
 		if trans {
 			col := make([]float64, ar)
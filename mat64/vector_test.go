@@ -1,6 +1,7 @@
 package mat64
 
 import (
+	"math"
 	"math/rand"
 	"reflect"
 	"testing"
@@ -344,6 +345,315 @@ func TestVectorDivElem(t *testing.T) {
 	}
 }
 
+func TestCosineAngle(t *testing.T) {
+	for _, test := range []struct {
+		a, b  *Vector
+		cos   float64
+		angle float64
+	}{
+		{
+			a:   NewVector(3, []float64{1, 2, 3}),
+			b:   NewVector(3, []float64{1, 2, 3}),
+			cos: 1, angle: 0,
+		},
+		{
+			a:   NewVector(3, []float64{1, 2, 3}),
+			b:   NewVector(3, []float64{2, 4, 6}),
+			cos: 1, angle: 0,
+		},
+		{
+			a:   NewVector(2, []float64{1, 1}),
+			b:   NewVector(2, []float64{-1, 1}),
+			cos: 0, angle: math.Pi / 2,
+		},
+		{
+			a:   NewVector(2, []float64{1, 0}),
+			b:   NewDense(2, 2, []float64{1, 0, 1, 0}).ColView(0),
+			cos: math.Sqrt2 / 2, angle: math.Pi / 4,
+		},
+		{
+			a:   NewVector(2, []float64{1, 2}),
+			b:   NewVector(2, []float64{-3, -6}),
+			cos: -1, angle: math.Pi,
+		},
+	} {
+		if cos := Cosine(test.a, test.b); math.Abs(cos-test.cos) > 1e-15 {
+			t.Errorf("unexpected cosine: got: %v want: %v", cos, test.cos)
+		}
+		if angle := Angle(test.a, test.b); math.Abs(angle-test.angle) > 1e-7 {
+			t.Errorf("unexpected angle: got: %v want: %v", angle, test.angle)
+		}
+	}
+
+	zero := NewVector(2, nil)
+	if cos := Cosine(zero, NewVector(2, []float64{1, 0})); !math.IsNaN(cos) {
+		t.Errorf("unexpected cosine for zero vector: got: %v want: NaN", cos)
+	}
+	if angle := Angle(NewVector(2, []float64{1, 0}), zero); !math.IsNaN(angle) {
+		t.Errorf("unexpected angle for zero vector: got: %v want: NaN", angle)
+	}
+	panicked, message := panics(func() { Cosine(NewVector(2, nil), NewVector(3, nil)) })
+	if !panicked || message != matrix.ErrShape.Error() {
+		t.Errorf("expected shape panic for mismatched lengths")
+	}
+}
+
+func TestVectorProject(t *testing.T) {
+	for _, test := range []struct {
+		a, onto *Vector
+		want    *Vector
+	}{
+		{
+			a:    NewVector(2, []float64{3, 4}),
+			onto: NewVector(2, []float64{2, 0}),
+			want: NewVector(2, []float64{3, 0}),
+		},
+		{
+			a:    NewVector(3, []float64{1, 2, 3}),
+			onto: NewVector(3, []float64{1, 1, 1}),
+			want: NewVector(3, []float64{2, 2, 2}),
+		},
+		{
+			a:    NewVector(3, []float64{1, -2, 5}),
+			onto: NewDense(3, 2, []float64{2, 0, -1, 0, 3, 0}).ColView(0),
+			want: NewVector(3, []float64{38.0 / 14, -19.0 / 14, 57.0 / 14}),
+		},
+		{
+			a:    NewVector(2, []float64{3, 4}),
+			onto: NewVector(2, nil),
+			want: NewVector(2, nil),
+		},
+	} {
+		var p Vector
+		p.Project(test.a, test.onto)
+		if !EqualApprox(&p, test.want, 1e-14) {
+			t.Errorf("unexpected projection: got: %v want: %v", p.RawVector().Data, test.want.RawVector().Data)
+		}
+
+		// The residual must be orthogonal to the direction projected onto.
+		var res Vector
+		res.SubVec(test.a, &p)
+		if d := Dot(&res, test.onto); math.Abs(d) > 1e-14 {
+			t.Errorf("residual not orthogonal to direction: got dot product: %v", d)
+		}
+
+		// Projecting into either operand must give the same result.
+		a := NewVector(test.a.Len(), nil)
+		a.CopyVec(test.a)
+		a.Project(a, test.onto)
+		if !EqualApprox(a, test.want, 1e-14) {
+			t.Errorf("unexpected in place projection: got: %v want: %v", a.RawVector().Data, test.want.RawVector().Data)
+		}
+		onto := NewVector(test.onto.Len(), nil)
+		onto.CopyVec(test.onto)
+		onto.Project(test.a, onto)
+		if !EqualApprox(onto, test.want, 1e-14) {
+			t.Errorf("unexpected in place projection: got: %v want: %v", onto.RawVector().Data, test.want.RawVector().Data)
+		}
+	}
+
+	panicked, message := panics(func() { new(Vector).Project(NewVector(2, nil), NewVector(3, nil)) })
+	if !panicked || message != matrix.ErrShape.Error() {
+		t.Errorf("expected shape panic for mismatched lengths")
+	}
+}
+
+func TestVectorNormalize(t *testing.T) {
+	for _, test := range []struct {
+		a    *Vector
+		p    float64
+		want *Vector
+	}{
+		{
+			a:    NewVector(2, []float64{3, 4}),
+			p:    2,
+			want: NewVector(2, []float64{0.6, 0.8}),
+		},
+		{
+			a:    NewDense(3, 2, []float64{1, 0, -2, 0, 2, 0}).ColView(0),
+			p:    2,
+			want: NewVector(3, []float64{1.0 / 3, -2.0 / 3, 2.0 / 3}),
+		},
+		{
+			a:    NewVector(3, []float64{1, -2, 1}),
+			p:    1,
+			want: NewVector(3, []float64{0.25, -0.5, 0.25}),
+		},
+		{
+			a:    NewVector(3, []float64{1, -4, 2}),
+			p:    math.Inf(1),
+			want: NewVector(3, []float64{0.25, -1, 0.5}),
+		},
+	} {
+		var v Vector
+		if err := v.Normalize(test.a, test.p); err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		if !EqualApprox(&v, test.want, 1e-15) {
+			t.Errorf("unexpected normalized vector: got: %v want: %v", v.RawVector().Data, test.want.RawVector().Data)
+		}
+		if test.p == 2 {
+			if n := blas64.Nrm2(v.Len(), v.RawVector()); math.Abs(n-1) > 1e-15 {
+				t.Errorf("unexpected 2-norm: got: %v want: 1", n)
+			}
+		}
+	}
+
+	v := NewVector(2, []float64{5, 6})
+	if err := v.Normalize(NewVector(2, nil), 2); err != matrix.ErrZeroMatrix {
+		t.Errorf("unexpected error for zero vector: got: %v want: %v", err, matrix.ErrZeroMatrix)
+	}
+	if v.At(0, 0) != 5 || v.At(1, 0) != 6 {
+		t.Errorf("receiver modified by failed normalization: got: %v", v.RawVector().Data)
+	}
+	panicked, message := panics(func() { v.Normalize(v, 0) })
+	if !panicked || message != matrix.ErrNormOrder.Error() {
+		t.Errorf("expected norm order panic for non-positive p")
+	}
+}
+
+func TestVectorProjectSimplex(t *testing.T) {
+	for _, test := range []struct {
+		a    *Vector
+		want *Vector
+	}{
+		{
+			a:    NewVector(3, []float64{0.2, 0.3, 0.5}),
+			want: NewVector(3, []float64{0.2, 0.3, 0.5}),
+		},
+		{
+			a:    NewVector(3, []float64{1, 1, 1}),
+			want: NewVector(3, []float64{1.0 / 3, 1.0 / 3, 1.0 / 3}),
+		},
+		{
+			a:    NewVector(3, []float64{2, 0, -1}),
+			want: NewVector(3, []float64{1, 0, 0}),
+		},
+		{
+			a:    NewVector(4, []float64{0.5, -1, 0.9, 0.1}),
+			want: NewVector(4, []float64{0.3, 0, 0.7, 0}),
+		},
+		{
+			a:    NewDense(3, 2, []float64{-3, 0, -2, 0, -2, 0}).ColView(0),
+			want: NewVector(3, []float64{0, 0.5, 0.5}),
+		},
+		{
+			a:    NewVector(1, []float64{-7}),
+			want: NewVector(1, []float64{1}),
+		},
+	} {
+		var v Vector
+		v.ProjectSimplex(test.a)
+		if !EqualApprox(&v, test.want, 1e-14) {
+			t.Errorf("unexpected projection: got: %v want: %v", v.RawVector().Data, test.want.RawVector().Data)
+		}
+		var sum float64
+		for i := 0; i < v.Len(); i++ {
+			e := v.At(i, 0)
+			if e < 0 {
+				t.Errorf("negative element %d in projection: %v", i, e)
+			}
+			sum += e
+		}
+		if math.Abs(sum-1) > 1e-14 {
+			t.Errorf("projection does not sum to one: got: %v", sum)
+		}
+
+		// The projection onto a convex set is nearer to a than any
+		// other point in the set, so check against a set of
+		// random points on the simplex.
+		rnd := rand.New(rand.NewSource(1))
+		var d Vector
+		d.SubVec(test.a, &v)
+		dist := Norm(&d, 2)
+		w := NewVector(v.Len(), nil)
+		for k := 0; k < 100; k++ {
+			for i := 0; i < w.Len(); i++ {
+				w.SetVec(i, rnd.ExpFloat64())
+			}
+			w.Normalize(w, 1)
+			d.SubVec(test.a, w)
+			if n := Norm(&d, 2); n < dist-1e-14 {
+				t.Errorf("found nearer point on the simplex: %v at %v < %v", w.RawVector().Data, n, dist)
+				break
+			}
+		}
+
+		// Projecting in place must give the same result.
+		a := NewVector(test.a.Len(), nil)
+		a.CopyVec(test.a)
+		a.ProjectSimplex(a)
+		if !EqualApprox(a, test.want, 1e-14) {
+			t.Errorf("unexpected in place projection: got: %v want: %v", a.RawVector().Data, test.want.RawVector().Data)
+		}
+	}
+}
+
+func TestMeanVec(t *testing.T) {
+	a := NewDense(4, 3, []float64{
+		1, 2, 3,
+		4, 5, 6,
+		-1, 0, 2,
+		7, -3, 1,
+	})
+	for _, test := range []struct {
+		a       Matrix
+		weights []float64
+	}{
+		{a: a},
+		{a: a, weights: []float64{1, 1, 1, 1}},
+		{a: a, weights: []float64{0.5, 2, 0, 1.5}},
+		{a: a.T(), weights: []float64{3, 1, 2}},
+	} {
+		r, c := test.a.Dims()
+		want := make([]float64, c)
+		var sumW float64
+		for i := 0; i < r; i++ {
+			w := 1.0
+			if test.weights != nil {
+				w = test.weights[i]
+			}
+			sumW += w
+			for j := 0; j < c; j++ {
+				want[j] += w * test.a.At(i, j)
+			}
+		}
+		for j := range want {
+			want[j] /= sumW
+		}
+
+		var v Vector
+		v.MeanVec(test.a, test.weights)
+		if !EqualApprox(&v, NewVector(c, want), 1e-14) {
+			t.Errorf("unexpected mean for weights %v: got: %v want: %v", test.weights, v.RawVector().Data, want)
+		}
+	}
+
+	// Taking the mean into a view of the input must give the same result.
+	m := DenseCopyOf(a.View(0, 0, 3, 3))
+	v := m.ColView(0)
+	v.MeanVec(m, nil)
+	if want := NewVector(3, []float64{4.0 / 3, 7.0 / 3, 11.0 / 3}); !EqualApprox(v, want, 1e-14) {
+		t.Errorf("unexpected mean into view: got: %v want: %v", Col(nil, 0, m), want.RawVector().Data)
+	}
+
+	// Weights summing to zero give a NaN mean, including for columns
+	// with a non-zero weighted sum.
+	var zero Vector
+	zero.MeanVec(a, []float64{1, -1, 2, -2})
+	for j := 0; j < zero.Len(); j++ {
+		if !math.IsNaN(zero.At(j, 0)) {
+			t.Errorf("unexpected mean for zero weight sum at %d: got: %v want: NaN", j, zero.At(j, 0))
+		}
+	}
+
+	panicked, message := panics(func() { new(Vector).MeanVec(a, []float64{1, 2}) })
+	if !panicked || message != matrix.ErrShape.Error() {
+		t.Errorf("expected shape panic for mismatched weights")
+	}
+}
+
 func BenchmarkAddScaledVec10Inc1(b *testing.B)      { addScaledVecBench(b, 10, 1) }
 func BenchmarkAddScaledVec100Inc1(b *testing.B)     { addScaledVecBench(b, 100, 1) }
 func BenchmarkAddScaledVec1000Inc1(b *testing.B)    { addScaledVecBench(b, 1000, 1) }
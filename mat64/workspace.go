@@ -0,0 +1,41 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+// Workspace holds scratch memory that can be reused between calls to the
+// FactorizeInto methods of LU, QR and Cholesky, avoiding allocation when
+// matrices are repeatedly factorized, for example within an optimizer.
+// A Workspace grows as needed to accommodate larger matrices. The zero
+// value is ready to use. A Workspace must not be used concurrently.
+type Workspace struct {
+	work  []float64
+	iwork []int
+}
+
+// floats returns a float64 slice of length n backed by the workspace. If ws
+// is nil, a new slice is allocated. The contents of the returned slice are
+// undefined.
+func (ws *Workspace) floats(n int) []float64 {
+	if ws == nil {
+		return make([]float64, n)
+	}
+	if cap(ws.work) < n {
+		ws.work = make([]float64, n)
+	}
+	return ws.work[:n]
+}
+
+// ints returns an int slice of length n backed by the workspace. If ws
+// is nil, a new slice is allocated. The contents of the returned slice are
+// undefined.
+func (ws *Workspace) ints(n int) []int {
+	if ws == nil {
+		return make([]int, n)
+	}
+	if cap(ws.iwork) < n {
+		ws.iwork = make([]int, n)
+	}
+	return ws.iwork[:n]
+}
@@ -0,0 +1,166 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/floats"
+)
+
+func TestFactorizeInto(t *testing.T) {
+	var (
+		ws   Workspace
+		lu   LU
+		qr   QR
+		chol Cholesky
+	)
+	// The sizes grow and shrink to exercise reuse of the
+	// receivers and the workspace.
+	for _, n := range []int{1, 3, 10, 10, 4, 25, 7} {
+		a := NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, rand.NormFloat64())
+			}
+		}
+		s := NewSymDense(n, nil)
+		s.SymOuterK(1, a)
+		for i := 0; i < n; i++ {
+			s.SetSym(i, i, s.At(i, i)+1)
+		}
+
+		var wantLU LU
+		wantLU.Factorize(a)
+		lu.FactorizeInto(a, &ws)
+		if !Equal(lu.lu, wantLU.lu) {
+			t.Errorf("unexpected LU factorization for n=%d", n)
+		}
+		if !equalInts(lu.pivot, wantLU.pivot) {
+			t.Errorf("unexpected LU pivots for n=%d: got: %v want: %v", n, lu.pivot, wantLU.pivot)
+		}
+		if lu.cond != wantLU.cond {
+			t.Errorf("unexpected LU condition number for n=%d: got: %v want: %v", n, lu.cond, wantLU.cond)
+		}
+
+		var wantQR QR
+		wantQR.Factorize(a)
+		qr.FactorizeInto(a, &ws)
+		if !Equal(qr.qr, wantQR.qr) {
+			t.Errorf("unexpected QR factorization for n=%d", n)
+		}
+		if !floats.Equal(qr.tau, wantQR.tau) {
+			t.Errorf("unexpected QR reflector scales for n=%d: got: %v want: %v", n, qr.tau, wantQR.tau)
+		}
+		if qr.cond != wantQR.cond {
+			t.Errorf("unexpected QR condition number for n=%d: got: %v want: %v", n, qr.cond, wantQR.cond)
+		}
+
+		var wantChol Cholesky
+		if !wantChol.Factorize(s) {
+			t.Fatalf("unexpected Cholesky factorization failure for n=%d", n)
+		}
+		if !chol.FactorizeInto(s, &ws) {
+			t.Errorf("unexpected Cholesky FactorizeInto failure for n=%d", n)
+		}
+		if !Equal(chol.chol, wantChol.chol) {
+			t.Errorf("unexpected Cholesky factorization for n=%d", n)
+		}
+		if chol.cond != wantChol.cond {
+			t.Errorf("unexpected Cholesky condition number for n=%d: got: %v want: %v", n, chol.cond, wantChol.cond)
+		}
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if v != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func BenchmarkLUFactorize(b *testing.B)           { benchmarkLUFactorize(b, false) }
+func BenchmarkLUFactorizeInto(b *testing.B)       { benchmarkLUFactorize(b, true) }
+func BenchmarkQRFactorize(b *testing.B)           { benchmarkQRFactorize(b, false) }
+func BenchmarkQRFactorizeInto(b *testing.B)       { benchmarkQRFactorize(b, true) }
+func BenchmarkCholeskyFactorize(b *testing.B)     { benchmarkCholeskyFactorize(b, false) }
+func BenchmarkCholeskyFactorizeInto(b *testing.B) { benchmarkCholeskyFactorize(b, true) }
+
+const factorizeBenchSize = 50
+
+func factorizeBenchMatrix(n int) *Dense {
+	a := NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			a.Set(i, j, rand.NormFloat64())
+		}
+	}
+	return a
+}
+
+func benchmarkLUFactorize(b *testing.B, reuse bool) {
+	a := factorizeBenchMatrix(factorizeBenchSize)
+	var (
+		ws Workspace
+		lu LU
+	)
+	lu.FactorizeInto(a, &ws)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if reuse {
+			lu.FactorizeInto(a, &ws)
+		} else {
+			lu.Factorize(a)
+		}
+	}
+}
+
+func benchmarkQRFactorize(b *testing.B, reuse bool) {
+	a := factorizeBenchMatrix(factorizeBenchSize)
+	var (
+		ws Workspace
+		qr QR
+	)
+	qr.FactorizeInto(a, &ws)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if reuse {
+			qr.FactorizeInto(a, &ws)
+		} else {
+			qr.Factorize(a)
+		}
+	}
+}
+
+func benchmarkCholeskyFactorize(b *testing.B, reuse bool) {
+	n := factorizeBenchSize
+	s := NewSymDense(n, nil)
+	s.SymOuterK(1, factorizeBenchMatrix(n))
+	for i := 0; i < n; i++ {
+		s.SetSym(i, i, s.At(i, i)+1)
+	}
+	var (
+		ws   Workspace
+		chol Cholesky
+	)
+	chol.FactorizeInto(s, &ws)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if reuse {
+			chol.FactorizeInto(s, &ws)
+		} else {
+			chol.Factorize(s)
+		}
+	}
+}